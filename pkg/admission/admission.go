@@ -0,0 +1,152 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// KindLighthouseJob the kind of lighthouse jobs which are validated
+	KindLighthouseJob = "LighthouseJob"
+
+	// KindPipelineRun the kind of tekton pipeline runs which are validated
+	KindPipelineRun = "PipelineRun"
+
+	// ValidatePath the default HTTP path of the validating webhook
+	ValidatePath = "/validate"
+
+	// HealthPath the HTTP path used for liveness and readiness probes
+	HealthPath = "/healthz"
+)
+
+// ValidateObject validates the raw JSON of a LighthouseJob or PipelineRun using the same rules as 'jx pipeline lint'.
+// Any other kinds of resource are allowed
+func ValidateObject(ctx context.Context, kind string, raw []byte) error {
+	switch kind {
+	case KindLighthouseJob:
+		lhjob := &v1alpha1.LighthouseJob{}
+		err := json.Unmarshal(raw, lhjob)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal LighthouseJob")
+		}
+		return ValidateLighthouseJob(ctx, lhjob)
+
+	case KindPipelineRun:
+		pr := &v1beta1.PipelineRun{}
+		err := json.Unmarshal(raw, pr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal PipelineRun")
+		}
+		fieldError := pipelines.ValidatePipelineRun(ctx, pr)
+		if fieldError != nil {
+			return fieldError
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+// ValidateLighthouseJob validates the PipelineRun which would be created for the LighthouseJob
+func ValidateLighthouseJob(ctx context.Context, lhjob *v1alpha1.LighthouseJob) error {
+	if lhjob.Spec.PipelineRunSpec == nil {
+		return nil
+	}
+	name := lhjob.Name
+	if name == "" {
+		name = lhjob.GenerateName
+	}
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: lhjob.Namespace,
+		},
+		Spec: *lhjob.Spec.PipelineRunSpec,
+	}
+	fieldError := pipelines.ValidatePipelineRun(ctx, pr)
+	if fieldError != nil {
+		return fieldError
+	}
+	return nil
+}
+
+// Review returns the response for the given admission request
+func Review(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{
+		UID:     request.UID,
+		Allowed: true,
+	}
+	if request.Operation == admissionv1.Delete || len(request.Object.Raw) == 0 {
+		return response
+	}
+	err := ValidateObject(ctx, request.Kind.Kind, request.Object.Raw)
+	if err != nil {
+		log.Logger().Infof("rejected %s %s in namespace %s: %s", request.Kind.Kind, request.Name, request.Namespace, err.Error())
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("%s failed pipeline validation: %s", request.Kind.Kind, err.Error()),
+		}
+	}
+	return response
+}
+
+// Handler an HTTP handler of AdmissionReview requests
+type Handler struct{}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	err = json.Unmarshal(data, review)
+	if err != nil || review.Request == nil {
+		http.Error(w, "request body is not an AdmissionReview", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = Review(r.Context(), review.Request)
+	review.Request = nil
+
+	data, err = json.Marshal(review)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal response: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(data)
+	if err != nil {
+		log.Logger().Warnf("failed to write admission response: %s", err.Error())
+	}
+}
+
+// NewServeMux creates the HTTP handlers for the webhook and its health checks
+func NewServeMux(path string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(path, &Handler{})
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
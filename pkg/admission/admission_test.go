@@ -0,0 +1,156 @@
+package admission_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/admission"
+	"github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAdmissionWebhook(t *testing.T) {
+	server := httptest.NewServer(admission.NewServeMux(admission.ValidatePath))
+	defer server.Close()
+
+	pipelineRunKind := metav1.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: admission.KindPipelineRun}
+	lighthouseJobKind := metav1.GroupVersionKind{Group: "lighthouse.jenkins.io", Version: "v1alpha1", Kind: admission.KindLighthouseJob}
+
+	testCases := []struct {
+		file    string
+		kind    metav1.GroupVersionKind
+		allowed bool
+	}{
+		{
+			file:    "valid.yaml",
+			kind:    pipelineRunKind,
+			allowed: true,
+		},
+		{
+			file:    "missing_volume.yaml",
+			kind:    pipelineRunKind,
+			allowed: false,
+		},
+		{
+			file:    "lighthousejob_valid.yaml",
+			kind:    lighthouseJobKind,
+			allowed: true,
+		},
+		{
+			file:    "lighthousejob_no_pipeline.yaml",
+			kind:    lighthouseJobKind,
+			allowed: true,
+		},
+		{
+			file:    "lighthousejob_missing_volume.yaml",
+			kind:    lighthouseJobKind,
+			allowed: false,
+		},
+		{
+			file:    "lighthousejob_invalid_generate_name.yaml",
+			kind:    lighthouseJobKind,
+			allowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		path := filepath.Join("test_data", tc.file)
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err, "failed to load %s", path)
+		raw, err := yaml.YAMLToJSON(data)
+		require.NoError(t, err, "failed to convert %s to JSON", path)
+
+		uid := types.UID("uid-" + tc.file)
+		review := &admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "admission.k8s.io/v1",
+				Kind:       "AdmissionReview",
+			},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       uid,
+				Kind:      tc.kind,
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		body, err := json.Marshal(review)
+		require.NoError(t, err, "failed to marshal AdmissionReview")
+
+		resp, err := http.Post(server.URL+admission.ValidatePath, "application/json", bytes.NewReader(body))
+		require.NoError(t, err, "failed to post AdmissionReview for %s", path)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "status code for %s", path)
+
+		result := &admissionv1.AdmissionReview{}
+		err = json.NewDecoder(resp.Body).Decode(result)
+		resp.Body.Close()
+		require.NoError(t, err, "failed to decode response for %s", path)
+		require.NotNil(t, result.Response, "response for %s", path)
+
+		assert.Equal(t, uid, result.Response.UID, "response UID for %s", path)
+		assert.Equal(t, tc.allowed, result.Response.Allowed, "allowed for %s", path)
+		if !tc.allowed {
+			require.NotNil(t, result.Response.Result, "result for %s", path)
+			t.Logf("%s was rejected with: %s\n", path, result.Response.Result.Message)
+		}
+	}
+}
+
+func TestValidateLighthouseJob(t *testing.T) {
+	ctx := context.Background()
+	spec := &v1beta1.PipelineRunSpec{
+		PipelineSpec: &v1beta1.PipelineSpec{
+			Tasks: []v1beta1.PipelineTask{
+				{
+					Name: "build",
+					TaskSpec: &v1beta1.EmbeddedTask{
+						TaskSpec: v1beta1.TaskSpec{
+							Steps: []v1beta1.Step{
+								{
+									Container: corev1.Container{
+										Name:    "build",
+										Image:   "golang:1.15",
+										Command: []string{"make", "build"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	lhjob := &v1alpha1.LighthouseJob{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "myorg-myrepo-",
+		},
+		Spec: v1alpha1.LighthouseJobSpec{
+			PipelineRunSpec: spec,
+		},
+	}
+	err := admission.ValidateLighthouseJob(ctx, lhjob)
+	require.NoError(t, err, "should allow a valid LighthouseJob")
+
+	lhjob.GenerateName = "myorg.myrepo-"
+	err = admission.ValidateLighthouseJob(ctx, lhjob)
+	require.Error(t, err, "should validate the generated name of the PipelineRun")
+	t.Logf("invalid generate name was rejected with: %s\n", err.Error())
+
+	lhjob.Spec.PipelineRunSpec = nil
+	err = admission.ValidateLighthouseJob(ctx, lhjob)
+	require.NoError(t, err, "should allow a LighthouseJob without a PipelineRunSpec")
+}
@@ -0,0 +1,62 @@
+package clusterconfig
+
+import (
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LastAppliedAnnotation the annotation added by kubectl apply which we do not export
+	LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	// DefaultConfigMaps the names of the pipeline related ConfigMaps which are exported by default.
+	// The lighthouse 'config' contains the trigger mappings along with the keeper and max concurrency settings used to throttle pipelines
+	DefaultConfigMaps = []string{"config", "plugins", "lighthouse-external-plugins"}
+
+	// DefaultCronJobs the names of the garbage collection CronJobs which are exported by default as their arguments define the retention policies of pipelines
+	DefaultCronJobs = []string{"jx-gcactivities", "jx-gcpods"}
+)
+
+// NamespaceDir returns the directory the resources of the given namespace are exported into
+// using the same layout as the 'config-root' folder of a cluster GitOps repository
+func NamespaceDir(dir, ns string) string {
+	return filepath.Join(dir, "namespaces", ns)
+}
+
+// ConfigMapPath returns the path of the exported ConfigMap file
+func ConfigMapPath(dir, ns, name string) string {
+	return filepath.Join(NamespaceDir(dir, ns), name+"-cm.yaml")
+}
+
+// CronJobPath returns the path of the exported CronJob file
+func CronJobPath(dir, ns, name string) string {
+	return filepath.Join(NamespaceDir(dir, ns), name+"-cronjob.yaml")
+}
+
+// SourceRepositoryPath returns the path of the exported SourceRepository file
+func SourceRepositoryPath(dir, ns, name string) string {
+	return filepath.Join(NamespaceDir(dir, ns), name+"-sr.yaml")
+}
+
+// ToExportedObjectMeta returns the metadata of a resource without any of the cluster specific values
+// such as the resource version and UID so that it can be committed to git and reapplied
+func ToExportedObjectMeta(m *metav1.ObjectMeta) metav1.ObjectMeta {
+	answer := metav1.ObjectMeta{
+		Name:      m.Name,
+		Namespace: m.Namespace,
+		Labels:    m.Labels,
+	}
+	for k, v := range m.Annotations {
+		if k == LastAppliedAnnotation {
+			continue
+		}
+		if answer.Annotations == nil {
+			answer.Annotations = map[string]string{}
+		}
+		answer.Annotations[k] = v
+	}
+	return answer
+}
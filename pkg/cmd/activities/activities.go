@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/ghodss/yaml"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
@@ -173,6 +175,13 @@ func (o *Options) addTableRow(t *table.Table, activity *v1.PipelineActivity) boo
 		if version != "" {
 			text = "Version: " + termcolor.ColorInfo(version)
 		}
+		owner := owners.ToString(activity.Annotations)
+		if owner != "" {
+			if text != "" {
+				text += " "
+			}
+			text += "Owner: " + termcolor.ColorInfo(owner)
+		}
 		statusText := statusString(activity.Spec.Status)
 		if statusText == "" {
 			statusText = text
@@ -321,10 +330,15 @@ func addStepRowItem(t *table.Table, step *v1.CoreActivityStep, indent, name, des
 	} else if name != "" {
 		textName = name + ":" + textName
 	}
+	status := statusString(step.Status)
+	if reason, skipped := pipelines.SkipReason(step); skipped {
+		status = termcolor.ColorWarning(pipelines.StatusSkipped)
+		text = reason
+	}
 	t.AddRow(indent+textName,
 		timeToString(step.StartedTimestamp),
 		DurationString(step.StartedTimestamp, step.CompletedTimestamp),
-		statusString(step.Status)+" "+text)
+		status+" "+text)
 }
 
 func statusString(statusType v1.ActivityStatusType) string {
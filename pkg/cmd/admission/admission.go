@@ -0,0 +1,109 @@
+package admission
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/admission"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Port        int
+	Path        string
+	TLSCertFile string
+	TLSKeyFile  string
+	Insecure    bool
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Runs the validating admission webhook which checks LighthouseJob and PipelineRun resources when they are created or updated.
+
+		The same rules as 'jx pipeline lint' are used so that invalid pipelines are rejected in the cluster even if the CLI checks were skipped.
+
+		See the charts/jx-pipeline-admission chart to deploy the webhook.
+`)
+
+	cmdExample = templates.Examples(`
+		# Runs the admission webhook using the TLS certificate mounted from a Secret
+		jx pipeline admission --tls-cert-file /etc/webhook/certs/tls.crt --tls-key-file /etc/webhook/certs/tls.key
+	`)
+)
+
+// NewCmdPipelineAdmission creates the command
+func NewCmdPipelineAdmission() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "admission",
+		Short:   "Runs the validating admission webhook for LighthouseJob and PipelineRun resources",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"admission-webhook"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().IntVarP(&o.Port, "port", "p", 8443, "The port to listen on")
+	cmd.Flags().StringVarP(&o.Path, "path", "", admission.ValidatePath, "The HTTP path of the validating webhook")
+	cmd.Flags().StringVarP(&o.TLSCertFile, "tls-cert-file", "", "/etc/webhook/certs/tls.crt", "The TLS certificate file")
+	cmd.Flags().StringVarP(&o.TLSKeyFile, "tls-key-file", "", "/etc/webhook/certs/tls.key", "The TLS private key file")
+	cmd.Flags().BoolVarP(&o.Insecure, "insecure", "", false, "Serves plain HTTP without TLS such as when TLS is terminated by a proxy")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	if o.Path == "" {
+		o.Path = admission.ValidatePath
+	}
+	if !o.Insecure {
+		if o.TLSCertFile == "" {
+			return options.MissingOption("tls-cert-file")
+		}
+		if o.TLSKeyFile == "" {
+			return options.MissingOption("tls-key-file")
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", o.Port),
+		Handler:           admission.NewServeMux(o.Path),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Logger().Infof("serving the admission webhook on port %s at path %s", info(o.Port), info(o.Path))
+	if o.Insecure {
+		err = server.ListenAndServe()
+	} else {
+		err = server.ListenAndServeTLS(o.TLSCertFile, o.TLSKeyFile)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return errors.Wrapf(err, "failed to serve the admission webhook on port %d", o.Port)
+	}
+	return nil
+}
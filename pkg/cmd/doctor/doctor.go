@@ -0,0 +1,589 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/constants"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/triggers"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/activities"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/naming"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/table"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-kube-client/v3/pkg/kubeclient"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	lhclient "github.com/jenkins-x/lighthouse-client/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/lighthouse-client/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	pipelineapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// StageTrigger verifies lighthouse has a trigger for the repository
+	StageTrigger = "trigger"
+
+	// StageWebhook verifies the webhook is registered for the repository
+	StageWebhook = "webhook"
+
+	// StagePush pushes a commit to the repository
+	StagePush = "push"
+
+	// StageLighthouseJob waits for the webhook to be delivered and a LighthouseJob to be created
+	StageLighthouseJob = "lighthouse job"
+
+	// StagePipelineRun waits for the LighthouseJob to create a PipelineRun
+	StagePipelineRun = "pipeline run"
+
+	// StageCompletion waits for the PipelineRun to complete successfully
+	StageCompletion = "completion"
+
+	// LighthouseWebhooksDeployment the name of the lighthouse Deployment which receives webhooks
+	LighthouseWebhooksDeployment = "lighthouse-webhooks"
+
+	// LighthouseTektonControllerDeployment the name of the lighthouse Deployment which creates PipelineRuns
+	LighthouseTektonControllerDeployment = "lighthouse-tekton-controller"
+
+	// DefaultFile the file modified in the canary repository to create a commit
+	DefaultFile = ".jx-doctor"
+
+	labelBaseSHA = "lighthouse.jenkins-x.io/baseSHA"
+)
+
+// Stages the stages of the pipeline stack in the order they are verified
+var Stages = []string{StageTrigger, StageWebhook, StagePush, StageLighthouseJob, StagePipelineRun, StageCompletion}
+
+// StageResult the result of verifying a stage
+type StageResult struct {
+	Name     string
+	Status   string
+	Duration time.Duration
+	Message  string
+}
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Owner               string
+	Repository          string
+	Branch              string
+	File                string
+	Sha                 string
+	GitUsername         string
+	GitToken            string
+	LighthouseConfigMap string
+	Namespace           string
+	WebhookDuration     time.Duration
+	WaitDuration        time.Duration
+	PollPeriod          time.Duration
+	Results             []*StageResult
+	Out                 io.Writer
+	CommandRunner       cmdrunner.CommandRunner
+	GitClient           gitclient.Interface
+	KubeClient          kubernetes.Interface
+	JXClient            versioned.Interface
+	LHClient            lhclient.Interface
+	TektonClient        tektonclient.Interface
+
+	sourceRepository *v1.SourceRepository
+	lighthouseJob    *v1alpha1.LighthouseJob
+	pipelineRun      *pipelineapi.PipelineRun
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Verifies the pipeline stack of the cluster works end to end.
+
+		A commit is pushed to a canary repository then the command waits for the webhook to be delivered, the LighthouseJob and PipelineRun to be created and the pipeline to complete. If any stage breaks it is reported along with diagnostics.
+
+		The canary repository should be a repository which has been imported into Jenkins X and is only used for this check as each run pushes a commit to it.
+`)
+
+	cmdExample = templates.Examples(`
+		# Verifies the pipeline stack using a canary repository
+		jx pipeline doctor --owner myorg --repo jx-canary
+
+		# Verifies the pipeline stack for a commit which has already been pushed
+		jx pipeline doctor --owner myorg --repo jx-canary --sha 1234abcd
+	`)
+)
+
+// NewCmdPipelineDoctor creates the command
+func NewCmdPipelineDoctor() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Verifies the pipeline stack of the cluster works end to end",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"smoke-test"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Owner, "owner", "o", "", "The owner of the canary repository")
+	cmd.Flags().StringVarP(&o.Repository, "repo", "r", "", "The name of the canary repository")
+	cmd.Flags().StringVarP(&o.Branch, "branch", "", "", "The branch to push the commit to. Defaults to the default branch of the canary repository")
+	cmd.Flags().StringVarP(&o.File, "file", "f", DefaultFile, "The file to modify in the canary repository to create the commit")
+	cmd.Flags().StringVarP(&o.Sha, "sha", "", "", "The sha of a commit which has already been pushed. If specified no commit is pushed")
+	cmd.Flags().StringVarP(&o.GitUsername, "git-username", "", "", "The git username used to push to the canary repository")
+	cmd.Flags().StringVarP(&o.GitToken, "git-token", "", "", "The git token used to push to the canary repository")
+	cmd.Flags().StringVarP(&o.LighthouseConfigMap, "configmap", "", constants.LighthouseConfigMapName, "The name of the Lighthouse ConfigMap to find the trigger configurations")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The namespace of lighthouse and the pipelines. Defaults to the current namespace")
+	cmd.Flags().DurationVarP(&o.WebhookDuration, "webhook-duration", "", time.Minute*2, "Maximum duration to wait for the webhook to be delivered and the LighthouseJob and PipelineRun to be created")
+	cmd.Flags().DurationVarP(&o.WaitDuration, "duration", "", time.Minute*20, "Maximum duration to wait for the pipeline to complete")
+	cmd.Flags().DurationVarP(&o.PollPeriod, "poll-period", "", time.Second*2, "Poll period when waiting for each stage")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	if o.Owner == "" {
+		return options.MissingOption("owner")
+	}
+	if o.Repository == "" {
+		return options.MissingOption("repo")
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.File == "" {
+		o.File = DefaultFile
+	}
+	if o.PollPeriod <= 0 {
+		o.PollPeriod = time.Second * 2
+	}
+	if o.CommandRunner == nil {
+		o.CommandRunner = cmdrunner.QuietCommandRunner
+	}
+	if o.GitClient == nil {
+		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+	}
+
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create kube client")
+	}
+	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the jx client")
+	}
+	o.LHClient, err = lighthouses.LazyCreateLHClient(o.LHClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the lighthouse client")
+	}
+	if o.TektonClient == nil {
+		f := kubeclient.NewFactory()
+		cfg, err := f.CreateKubeConfig()
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubernetes config")
+		}
+		o.TektonClient, err = tektonclient.NewForConfig(cfg)
+		if err != nil {
+			return errors.Wrap(err, "error building tekton client")
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	ctx := o.GetContext()
+	fullName := scm.Join(o.Owner, o.Repository)
+	log.Logger().Infof("verifying the pipeline stack using repository %s in namespace %s", info(fullName), info(o.Namespace))
+
+	checks := map[string]func(context.Context) (string, error){
+		StageTrigger:       o.checkTrigger,
+		StageWebhook:       o.checkWebhook,
+		StagePush:          o.pushCommit,
+		StageLighthouseJob: o.waitForLighthouseJob,
+		StagePipelineRun:   o.waitForPipelineRun,
+		StageCompletion:    o.waitForCompletion,
+	}
+
+	o.Results = nil
+	var failed *StageResult
+	var failure error
+	for _, name := range Stages {
+		result := &StageResult{Name: name}
+		o.Results = append(o.Results, result)
+		if failure != nil {
+			result.Status = "Not Run"
+			continue
+		}
+
+		log.Logger().Infof("verifying stage %s", info(name))
+		start := time.Now()
+		result.Message, failure = checks[name](ctx)
+		result.Duration = time.Since(start).Round(time.Second)
+		if failure != nil {
+			failed = result
+			result.Status = termcolor.ColorError("Failed")
+			result.Message = failure.Error()
+			continue
+		}
+		result.Status = info("OK")
+	}
+
+	o.renderResults()
+	if failed != nil {
+		return errors.Wrapf(failure, "the pipeline stack is broken at stage %s", failed.Name)
+	}
+	log.Logger().Infof("the pipeline stack for %s is working", info(fullName))
+	return nil
+}
+
+func (o *Options) renderResults() {
+	t := table.CreateTable(o.Out)
+	t.AddRow("STAGE", "STATUS", "DURATION", "DETAILS")
+	for _, r := range o.Results {
+		duration := ""
+		if r.Duration > 0 {
+			duration = r.Duration.String()
+		}
+		t.AddRow(r.Name, r.Status, duration, r.Message)
+	}
+	t.Render()
+}
+
+func (o *Options) checkTrigger(ctx context.Context) (string, error) {
+	fullName := scm.Join(o.Owner, o.Repository)
+	cfg, err := triggers.LoadLighthouseConfig(ctx, o.KubeClient, o.Namespace, o.LighthouseConfigMap, false)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to load lighthouse config")
+	}
+	if !containsRepositoryTrigger(cfg, fullName) {
+		return "", errors.Errorf("no trigger for %s in ConfigMap %s. check the boot job imported the repository via: jx admin log", fullName, o.LighthouseConfigMap)
+	}
+	return fmt.Sprintf("found trigger for %s in ConfigMap %s", fullName, o.LighthouseConfigMap), nil
+}
+
+func (o *Options) checkWebhook(ctx context.Context) (string, error) {
+	name := naming.ToValidName(o.Owner + "-" + o.Repository)
+	sr, err := o.JXClient.JenkinsV1().SourceRepositories(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find SourceRepository %s in namespace %s", name, o.Namespace)
+	}
+	o.sourceRepository = sr
+
+	value := ""
+	if sr.Annotations != nil {
+		value = sr.Annotations["webhook.jenkins-x.io"]
+	}
+	if value != "true" {
+		failure := ""
+		if sr.Annotations != nil {
+			failure = sr.Annotations["webhook.jenkins-x.io/error"]
+		}
+		if failure != "" {
+			return "", errors.Errorf("the webhook is not registered for SourceRepository %s: %s", name, failure)
+		}
+		return "", errors.Errorf("the webhook is not registered for SourceRepository %s. it could be related to the git token permissions", name)
+	}
+	return fmt.Sprintf("webhook registered for SourceRepository %s", name), nil
+}
+
+func (o *Options) pushCommit(ctx context.Context) (string, error) {
+	if o.Sha != "" {
+		return fmt.Sprintf("using existing commit %s", o.Sha), nil
+	}
+	sr := o.sourceRepository
+	cloneURL := sr.Spec.HTTPCloneURL
+	if cloneURL == "" {
+		cloneURL = sr.Spec.URL
+	}
+	if cloneURL == "" {
+		return "", errors.Errorf("SourceRepository %s has no clone URL", sr.Name)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "jx-doctor-")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// the credentials are passed via a credential store file so that the token never appears in the
+	// git command line which is included in any errors
+	helper, err := o.credentialHelper(cloneURL, tmpDir)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(tmpDir, "repo")
+	err = os.MkdirAll(dir, files.DefaultDirWritePermissions)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+
+	g := o.GitClient
+	_, err = g.Command(dir, withHelper(helper, "clone", cloneURL, ".")...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to clone %s", cloneURL)
+	}
+	if o.Branch != "" {
+		_, err = g.Command(dir, "checkout", o.Branch)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to checkout branch %s", o.Branch)
+		}
+	}
+
+	path := filepath.Join(dir, o.File)
+	text := fmt.Sprintf("verified by jx pipeline doctor at %s\n", time.Now().UTC().Format(time.RFC3339))
+	err = ioutil.WriteFile(path, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to save file %s", path)
+	}
+	err = gitclient.Add(g, dir, o.File)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to add file %s", o.File)
+	}
+	_, err = g.Command(dir, "commit", "-m", "chore: verify the pipeline stack via jx pipeline doctor")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to commit file %s", o.File)
+	}
+	_, err = g.Command(dir, withHelper(helper, "push", "origin", "HEAD")...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to push to %s", cloneURL)
+	}
+	o.Sha, err = gitclient.GetLatestCommitSha(g, dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the pushed commit sha")
+	}
+	return fmt.Sprintf("pushed commit %s", o.Sha), nil
+}
+
+// credentialHelper saves the git credentials for the clone URL in a credential store file in the given dir and
+// returns the git configuration to use it. If there is no token it returns blank so the local git credentials are used
+func (o *Options) credentialHelper(cloneURL, dir string) (string, error) {
+	gitInfo, err := giturl.ParseGitURL(cloneURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse git URL %s", cloneURL)
+	}
+	f := scmhelpers.Factory{
+		GitServerURL: gitInfo.HostURL(),
+		GitUsername:  o.GitUsername,
+		GitToken:     o.GitToken,
+	}
+	err = f.FindGitToken()
+	if err != nil || f.GitToken == "" {
+		log.Logger().Warnf("no git token found for %s so relying on the local git credentials", gitInfo.HostURL())
+		return "", nil
+	}
+	username := f.GitUsername
+	if username == "" {
+		username = "oauth2"
+	}
+
+	u, err := url.Parse(cloneURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse git URL %s", cloneURL)
+	}
+	credentials := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		User:   url.UserPassword(username, f.GitToken),
+	}
+	path := filepath.Join(dir, "git-credentials")
+	err = ioutil.WriteFile(path, []byte(credentials.String()+"\n"), 0600)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to save git credentials file %s", path)
+	}
+	return "credential.helper=store --file=" + path, nil
+}
+
+// withHelper prefixes the git arguments with the credential helper configuration if there is one
+func withHelper(helper string, args ...string) []string {
+	if helper == "" {
+		return args
+	}
+	return append([]string{"-c", helper}, args...)
+}
+
+func (o *Options) waitForLighthouseJob(ctx context.Context) (string, error) {
+	err := o.poll(o.WebhookDuration, func() (bool, error) {
+		list, err := o.LHClient.LighthouseV1alpha1().LighthouseJobs(o.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list LighthouseJobs in namespace %s", o.Namespace)
+		}
+		for i := range list.Items {
+			job := &list.Items[i]
+			refs := job.Spec.Refs
+			if refs != nil && refs.Org == o.Owner && refs.Repo == o.Repository && refs.BaseSHA == o.Sha {
+				o.lighthouseJob = job
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "no LighthouseJob created for commit %s. check the webhook deliveries in your git provider%s", o.Sha, o.deploymentDiagnostics(ctx, LighthouseWebhooksDeployment))
+	}
+	return fmt.Sprintf("LighthouseJob %s created", o.lighthouseJob.Name), nil
+}
+
+func (o *Options) waitForPipelineRun(ctx context.Context) (string, error) {
+	selector := labelBaseSHA + "=" + o.Sha
+	err := o.poll(o.WebhookDuration, func() (bool, error) {
+		list, err := o.TektonClient.TektonV1beta1().PipelineRuns(o.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list PipelineRuns in namespace %s", o.Namespace)
+		}
+		for i := range list.Items {
+			pr := &list.Items[i]
+			labels := pr.Labels
+			if activities.GetLabel(labels, activities.OwnerLabels) == o.Owner && activities.GetLabel(labels, activities.RepoLabels) == o.Repository {
+				o.pipelineRun = pr
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		diagnostics := ""
+		if o.lighthouseJob != nil {
+			job, err := o.LHClient.LighthouseV1alpha1().LighthouseJobs(o.Namespace).Get(ctx, o.lighthouseJob.Name, metav1.GetOptions{})
+			if err == nil {
+				o.lighthouseJob = job
+			}
+			diagnostics = fmt.Sprintf(". LighthouseJob %s has state %s", o.lighthouseJob.Name, o.lighthouseJob.Status.State)
+			if o.lighthouseJob.Status.Description != "" {
+				diagnostics += ": " + o.lighthouseJob.Status.Description
+			}
+		}
+		return "", errors.Wrapf(err, "no PipelineRun created for commit %s%s%s", o.Sha, diagnostics, o.deploymentDiagnostics(ctx, LighthouseTektonControllerDeployment))
+	}
+	return fmt.Sprintf("PipelineRun %s created", o.pipelineRun.Name), nil
+}
+
+func (o *Options) waitForCompletion(ctx context.Context) (string, error) {
+	name := o.pipelineRun.Name
+	err := o.poll(o.WaitDuration, func() (bool, error) {
+		pr, err := o.TektonClient.TektonV1beta1().PipelineRuns(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get PipelineRun %s in namespace %s", name, o.Namespace)
+		}
+		o.pipelineRun = pr
+		return tektonlog.PipelineRunIsComplete(pr), nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "PipelineRun %s did not complete. you can view the log via: jx pipeline log %s", name, scm.Join(o.Owner, o.Repository))
+	}
+
+	pr := o.pipelineRun
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil || !cond.IsTrue() {
+		return "", errors.Errorf("PipelineRun %s failed%s. you can view the log via: jx pipeline log %s", name, failedTaskDiagnostics(pr), scm.Join(o.Owner, o.Repository))
+	}
+	return fmt.Sprintf("PipelineRun %s succeeded", name), nil
+}
+
+// poll invokes the function every poll period until it returns true, fails or the duration has elapsed
+func (o *Options) poll(duration time.Duration, fn func() (bool, error)) error {
+	end := time.Now().Add(duration)
+	for {
+		done, err := fn()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(end) {
+			return errors.Errorf("timed out after %s", duration.String())
+		}
+		time.Sleep(o.PollPeriod)
+	}
+}
+
+// deploymentDiagnostics returns a description of the lighthouse Deployment if it is not ready
+func (o *Options) deploymentDiagnostics(ctx context.Context, name string) string {
+	d, err := o.KubeClient.AppsV1().Deployments(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf(". there is no Deployment %s in namespace %s", name, o.Namespace)
+		}
+		return ""
+	}
+	if d.Status.ReadyReplicas == 0 {
+		return fmt.Sprintf(". Deployment %s has no ready replicas", name)
+	}
+	return fmt.Sprintf(". check the log via: kubectl logs -n %s deploy/%s", o.Namespace, name)
+}
+
+// failedTaskDiagnostics returns the reasons of the failed tasks of the PipelineRun
+func failedTaskDiagnostics(pr *pipelineapi.PipelineRun) string {
+	var failures []string
+	for _, tr := range pr.Status.TaskRuns {
+		if tr == nil || tr.Status == nil {
+			continue
+		}
+		cond := tr.Status.GetCondition(apis.ConditionSucceeded)
+		if cond != nil && cond.Status == corev1.ConditionFalse {
+			failures = append(failures, fmt.Sprintf("task %s: %s", tr.PipelineTaskName, cond.Message))
+		}
+	}
+	if len(failures) == 0 {
+		cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+		if cond != nil && cond.Message != "" {
+			return ": " + cond.Message
+		}
+		return ""
+	}
+	return ": " + strings.Join(failures, ", ")
+}
+
+// containsRepositoryTrigger returns true if the trigger is setup for the repository
+func containsRepositoryTrigger(cfg *config.Config, fullName string) bool {
+	if cfg.Postsubmits[fullName] != nil {
+		return true
+	}
+	if cfg.InRepoConfig.Enabled != nil {
+		f := cfg.InRepoConfig.Enabled[fullName]
+		if f != nil {
+			return *f
+		}
+	}
+	return false
+}
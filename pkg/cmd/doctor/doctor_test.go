@@ -0,0 +1,232 @@
+package doctor_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/doctor"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	fakelh "github.com/jenkins-x/lighthouse-client/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/lighthouse-client/pkg/config"
+	"github.com/jenkins-x/lighthouse-client/pkg/config/job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	faketekton "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPipelineDoctor(t *testing.T) {
+	ns := "jx"
+	owner := "myorg"
+	repo := "jx-canary"
+	sha := "1234"
+
+	testCases := []struct {
+		name        string
+		webhook     string
+		noJob       bool
+		push        bool
+		prStatus    corev1.ConditionStatus
+		failedStage string
+	}{
+		{
+			name:     "working",
+			webhook:  "true",
+			prStatus: corev1.ConditionTrue,
+		},
+		{
+			name:     "push",
+			webhook:  "true",
+			push:     true,
+			prStatus: corev1.ConditionTrue,
+		},
+		{
+			name:        "no-webhook",
+			webhook:     "error",
+			failedStage: doctor.StageWebhook,
+		},
+		{
+			name:        "no-lighthouse-job",
+			webhook:     "true",
+			noJob:       true,
+			failedStage: doctor.StageLighthouseJob,
+		},
+		{
+			name:        "pipeline-failed",
+			webhook:     "true",
+			prStatus:    corev1.ConditionFalse,
+			failedStage: doctor.StageCompletion,
+		},
+	}
+
+	cfg := &config.Config{
+		JobConfig: config.JobConfig{
+			Postsubmits: map[string][]job.Postsubmit{
+				scm.Join(owner, repo): {
+					{
+						Base: job.Base{
+							Name:  "release",
+							Agent: job.TektonPipelineAgent,
+						},
+					},
+				},
+			},
+		},
+	}
+	configData, err := yaml.Marshal(cfg)
+	require.NoError(t, err, "failed to marshal lighthouse config %v to YAML", cfg)
+
+	for _, tc := range testCases {
+		name := tc.name
+
+		_, o := doctor.NewCmdPipelineDoctor()
+		o.Owner = owner
+		o.Repository = repo
+		o.Sha = sha
+		o.Namespace = ns
+		o.WebhookDuration = time.Millisecond
+		o.WaitDuration = time.Millisecond
+		o.PollPeriod = time.Millisecond
+		o.Ctx = context.Background()
+		out := &bytes.Buffer{}
+		o.Out = out
+
+		var commands []string
+		if tc.push {
+			o.Sha = ""
+			o.GitUsername = "myuser"
+			o.GitToken = "mytoken"
+			o.CommandRunner = func(c *cmdrunner.Command) (string, error) {
+				commands = append(commands, c.CLI())
+				if strings.Contains(c.CLI(), "rev-parse") {
+					return sha, nil
+				}
+				return "", nil
+			}
+		}
+
+		o.KubeClient = fake.NewSimpleClientset(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      o.LighthouseConfigMap,
+					Namespace: ns,
+				},
+				Data: map[string]string{
+					"config.yaml": string(configData),
+				},
+			},
+		)
+		o.JXClient = fakejx.NewSimpleClientset(
+			&v1.SourceRepository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      owner + "-" + repo,
+					Namespace: ns,
+					Annotations: map[string]string{
+						"webhook.jenkins-x.io": tc.webhook,
+					},
+				},
+				Spec: v1.SourceRepositorySpec{
+					HTTPCloneURL: "https://github.com/myorg/jx-canary.git",
+				},
+			},
+		)
+
+		var lhObjects []runtime.Object
+		if !tc.noJob {
+			lhObjects = append(lhObjects, &v1alpha1.LighthouseJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "myorg-jx-canary-abc",
+					Namespace: ns,
+				},
+				Spec: v1alpha1.LighthouseJobSpec{
+					Refs: &v1alpha1.Refs{
+						Org:     owner,
+						Repo:    repo,
+						BaseSHA: sha,
+					},
+				},
+			})
+		}
+		o.LHClient = fakelh.NewSimpleClientset(lhObjects...)
+
+		now := metav1.Now()
+		pr := &v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myorg-jx-canary-abc-1",
+				Namespace: ns,
+				Labels: map[string]string{
+					"lighthouse.jenkins-x.io/baseSHA":   sha,
+					"lighthouse.jenkins-x.io/refs.org":  owner,
+					"lighthouse.jenkins-x.io/refs.repo": repo,
+				},
+			},
+		}
+		pr.Status.CompletionTime = &now
+		pr.Status.Conditions = duckv1beta1.Conditions{
+			{
+				Type:    apis.ConditionSucceeded,
+				Status:  tc.prStatus,
+				Message: "task build failed",
+			},
+		}
+		o.TektonClient = faketekton.NewSimpleClientset(pr)
+
+		err = o.Run()
+		t.Logf("test %s generated report:\n%s\n", name, out.String())
+
+		if tc.failedStage == "" {
+			require.NoError(t, err, "failed to run test %s", name)
+			for _, r := range o.Results {
+				assert.NotEmpty(t, r.Message, "message for stage %s in test %s", r.Name, name)
+			}
+			if tc.push {
+				assert.Equal(t, sha, o.Sha, "pushed sha for test %s", name)
+				for _, c := range commands {
+					t.Logf("test %s ran: %s\n", name, c)
+					assert.NotContains(t, c, o.GitToken, "git command should not contain the token for test %s", name)
+				}
+				cli := strings.Join(commands, "\n")
+				assert.Contains(t, cli, "credential.helper=store", "git commands for test %s", name)
+				assert.Contains(t, cli, "clone https://github.com/myorg/jx-canary.git", "git commands for test %s", name)
+				assert.Contains(t, cli, "push origin HEAD", "git commands for test %s", name)
+			}
+			continue
+		}
+		require.Error(t, err, "should have failed for test %s", name)
+		assert.Contains(t, err.Error(), "stage "+tc.failedStage, "error for test %s", name)
+		if tc.failedStage != doctor.StageCompletion {
+			assert.Contains(t, out.String(), "Not Run", "report for test %s", name)
+		}
+		for _, r := range o.Results {
+			if r.Name == tc.failedStage {
+				assert.Contains(t, r.Status, "Failed", "status of stage %s for test %s", r.Name, name)
+			}
+		}
+	}
+}
+
+func TestPipelineDoctorFlags(t *testing.T) {
+	cmd, o := doctor.NewCmdPipelineDoctor()
+
+	err := cmd.ParseFlags([]string{"--owner", "myorg", "--repo", "jx-canary", "--branch", "canary", "-b"})
+	require.NoError(t, err, "failed to parse flags")
+
+	assert.Equal(t, "myorg", o.Owner, "owner")
+	assert.Equal(t, "jx-canary", o.Repository, "repo")
+	assert.Equal(t, "canary", o.Branch, "branch")
+	assert.True(t, o.BatchMode, "batch mode")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
 	"github.com/jenkins-x/lighthouse-client/pkg/util"
 
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/hooks"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/input"
@@ -38,6 +39,7 @@ type Options struct {
 	options.BaseOptions
 	lighthouses.ResolverOptions
 	DiscoverScm scmhelpers.Options
+	Hooks       hooks.Options
 
 	File          string
 	Namespace     string
@@ -102,6 +104,7 @@ func NewCmdPipelineEffective() (*cobra.Command, *Options) {
 	}
 
 	o.ResolverOptions.AddFlags(cmd)
+	o.Hooks.AddFlags(cmd)
 
 	cmd.Flags().StringVarP(&o.File, "file", "f", "", "The pipeline file to render")
 	cmd.Flags().StringVarP(&o.TriggerName, "trigger", "t", "", "The path to the trigger file. If not specified you will be prompted to choose one")
@@ -253,7 +256,7 @@ func (o *Options) processFile() error {
 	}
 
 	name := filepath.Base(path)
-	return o.displayPipeline(path, name, pr)
+	return o.displayPipeline(path, "", name, pr)
 }
 
 func (o *Options) processTriggers() error {
@@ -301,10 +304,11 @@ func (o *Options) processTriggers() error {
 		return errors.Wrapf(err, "failed to load %s", path)
 	}
 
-	return o.displayPipeline(trigger.Path, pipelineName, pipeline)
+	return o.displayPipeline(path, trigger.Path, pipelineName, pipeline)
 }
 
-func (o *Options) displayPipeline(path string, name string, pipeline *tektonv1beta1.PipelineRun) error {
+// displayPipeline displays the effective pipeline from the given pipeline file path along with the triggers file path if it was loaded via a trigger
+func (o *Options) displayPipeline(path, triggerPath, name string, pipeline *tektonv1beta1.PipelineRun) error {
 	if o.AddDefaults {
 		err := o.addPipelineParameterDefaults(path, name, pipeline)
 		if err != nil {
@@ -312,6 +316,16 @@ func (o *Options) displayPipeline(path string, name string, pipeline *tektonv1be
 		}
 	}
 
+	err := o.Hooks.Run(&hooks.Event{
+		Hook:        hooks.PostEffective,
+		Path:        path,
+		TriggerPath: triggerPath,
+		PipelineRun: pipeline,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to run hooks for %s", name)
+	}
+
 	// lets create an output file if using editor
 	if o.Editor != "" && o.OutFile == "" {
 		fileName := ""
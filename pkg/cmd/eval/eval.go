@@ -0,0 +1,379 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/outputformat"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/table"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig/inrepo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// StatusRun the task would run
+	StatusRun = "run"
+
+	// StatusSkip the task would be skipped
+	StatusSkip = "skip"
+
+	// StatusUnknown the task has when expressions which could not be resolved
+	StatusUnknown = "unknown"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+	lighthouses.ResolverOptions
+
+	File     string
+	Format   string
+	Params   []string
+	Values   []string
+	Resolver *inrepo.UsesResolver
+	Out      io.Writer
+	Results  []*TaskResult
+}
+
+// TaskResult the result of evaluating the when expressions of a pipeline task
+type TaskResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+
+	// Parent the skipped or unknown task which this task runs after or uses the results of
+	Parent string        `json:"parent,omitempty"`
+	Whens  []*WhenResult `json:"whens,omitempty"`
+}
+
+// WhenResult the result of evaluating a single when expression
+type WhenResult struct {
+	Input      string   `json:"input"`
+	Operator   string   `json:"operator"`
+	Values     []string `json:"values"`
+	Matched    bool     `json:"matched"`
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+var (
+	variableRegex = regexp.MustCompile(`\$\(([^)]+)\)`)
+
+	resultRegex = regexp.MustCompile(`\$\(\s*tasks\.([^.)\s]+)\.results\.`)
+
+	cmdLong = templates.LongDesc(`
+		Evaluates the tekton when expressions of a pipeline against the given values to show which tasks would run.
+
+		Parameter defaults in the pipeline are used unless they are overridden via --param. Any other variables, such as task results, can be specified via --value
+
+		A task which runs after or uses the results of a skipped task is skipped too.
+`)
+
+	cmdExample = templates.Examples(`
+		# Evaluate the when expressions of a pipeline
+		jx pipeline eval -f .lighthouse/jenkins-x/release.yaml
+
+		# Evaluate the when expressions with a parameter value
+		jx pipeline eval -f .lighthouse/jenkins-x/pullrequest.yaml --param PULL_BASE_REF=main
+
+		# Evaluate the when expressions with a task result value
+		jx pipeline eval -f .lighthouse/jenkins-x/release.yaml --value tasks.build.results.changed=true
+	`)
+)
+
+// NewCmdPipelineEval creates the command
+func NewCmdPipelineEval() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "eval",
+		Short:   "Evaluates the when expressions of a pipeline to show which tasks would run",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"evaluate"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	o.ResolverOptions.AddFlags(cmd)
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "The pipeline file to evaluate")
+	cmd.Flags().StringVarP(&o.Format, "format", "", "", "The output format such as 'yaml' or 'json'")
+	cmd.Flags().StringArrayVarP(&o.Params, "param", "", nil, "List of name=value pipeline parameters which override the parameter defaults (can be use multiple times)")
+	cmd.Flags().StringArrayVarP(&o.Values, "value", "", nil, "List of name=value variables such as 'tasks.build.results.changed=true' (can be use multiple times)")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies settings
+func (o *Options) Validate() error {
+	err := o.BaseOptions.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate base options")
+	}
+	if o.File == "" {
+		return options.MissingOption("file")
+	}
+	if o.Resolver == nil {
+		o.Resolver, err = o.ResolverOptions.CreateResolver()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create a UsesResolver")
+		}
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	path := o.File
+	pr, err := lighthouses.LoadEffectivePipelineRun(o.Resolver, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+
+	values := DefaultValues(pr)
+	for _, p := range o.Params {
+		paths := strings.SplitN(p, "=", 2)
+		if len(paths) != 2 {
+			return options.InvalidOptionf("param", p, "should be of the form 'name=value'")
+		}
+		values["params."+paths[0]] = paths[1]
+	}
+	for _, v := range o.Values {
+		paths := strings.SplitN(v, "=", 2)
+		if len(paths) != 2 {
+			return options.InvalidOptionf("value", v, "should be of the form 'name=value'")
+		}
+		values[paths[0]] = paths[1]
+	}
+
+	o.Results = EvaluatePipelineRun(pr, values)
+
+	if o.Format != "" {
+		return outputformat.Marshal(o.Results, o.Out, o.Format)
+	}
+
+	t := table.CreateTable(o.Out)
+	t.AddRow("TASK", "STATUS", "PARENT", "WHEN", "MATCHED", "UNRESOLVED")
+	for _, r := range o.Results {
+		if len(r.Whens) == 0 {
+			t.AddRow(r.Name, r.Status, r.Parent, "", "", "")
+			continue
+		}
+		for i, w := range r.Whens {
+			name := ""
+			status := ""
+			parent := ""
+			if i == 0 {
+				name = r.Name
+				status = r.Status
+				parent = r.Parent
+			}
+			when := fmt.Sprintf("%s %s [%s]", w.Input, w.Operator, strings.Join(w.Values, ", "))
+			t.AddRow(name, status, parent, when, fmt.Sprintf("%v", w.Matched), strings.Join(w.Unresolved, ", "))
+		}
+	}
+	t.Render()
+	return nil
+}
+
+// DefaultValues returns the variable values from the parameter defaults and values in the PipelineRun
+func DefaultValues(pr *tektonv1beta1.PipelineRun) map[string]string {
+	values := map[string]string{}
+	ps := pr.Spec.PipelineSpec
+	if ps != nil {
+		for i := range ps.Params {
+			p := &ps.Params[i]
+			if p.Default != nil && p.Default.Type != tektonv1beta1.ParamTypeArray {
+				values["params."+p.Name] = p.Default.StringVal
+			}
+		}
+	}
+	for i := range pr.Spec.Params {
+		p := &pr.Spec.Params[i]
+		if p.Value.Type != tektonv1beta1.ParamTypeArray {
+			values["params."+p.Name] = p.Value.StringVal
+		}
+	}
+	if pr.Name != "" {
+		values["context.pipelineRun.name"] = pr.Name
+	}
+	return values
+}
+
+// EvaluatePipelineRun evaluates the when expressions of all the tasks in the given PipelineRun.
+// Like tekton, a task which runs after or uses the results of a skipped task is skipped too
+func EvaluatePipelineRun(pr *tektonv1beta1.PipelineRun, values map[string]string) []*TaskResult {
+	var answer []*TaskResult
+	ps := pr.Spec.PipelineSpec
+	if ps == nil {
+		return answer
+	}
+
+	tasks := map[string]*tektonv1beta1.PipelineTask{}
+	for i := range ps.Tasks {
+		tasks[ps.Tasks[i].Name] = &ps.Tasks[i]
+	}
+	results := map[string]*TaskResult{}
+	var evaluate func(pt *tektonv1beta1.PipelineTask) *TaskResult
+	evaluate = func(pt *tektonv1beta1.PipelineTask) *TaskResult {
+		r := results[pt.Name]
+		if r != nil {
+			return r
+		}
+		r = EvaluateTask(pt, values)
+
+		// lets register the result before evaluating the parents so that an invalid cycle terminates
+		results[pt.Name] = r
+		for _, name := range TaskDependencies(pt) {
+			parent := tasks[name]
+			if parent == nil || parent == pt {
+				continue
+			}
+			p := evaluate(parent)
+			switch {
+			case p.Status == StatusSkip && r.Status != StatusSkip:
+				r.Status = StatusSkip
+				r.Parent = name
+			case p.Status == StatusUnknown && r.Status == StatusRun:
+				r.Status = StatusUnknown
+				r.Parent = name
+			}
+		}
+		return r
+	}
+
+	for i := range ps.Tasks {
+		answer = append(answer, evaluate(&ps.Tasks[i]))
+	}
+	for i := range ps.Finally {
+		answer = append(answer, evaluate(&ps.Finally[i]))
+	}
+	return answer
+}
+
+// TaskDependencies returns the sorted names of the tasks which the given pipeline task runs after or uses the results of
+func TaskDependencies(pt *tektonv1beta1.PipelineTask) []string {
+	names := map[string]bool{}
+	for _, name := range pt.RunAfter {
+		names[name] = true
+	}
+	addResults := func(text string) {
+		for _, m := range resultRegex.FindAllStringSubmatch(text, -1) {
+			names[m[1]] = true
+		}
+	}
+	for i := range pt.Params {
+		v := &pt.Params[i].Value
+		addResults(v.StringVal)
+		for _, a := range v.ArrayVal {
+			addResults(a)
+		}
+	}
+	for i := range pt.WhenExpressions {
+		we := &pt.WhenExpressions[i]
+		addResults(we.Input)
+		for _, v := range we.Values {
+			addResults(v)
+		}
+	}
+	if pt.Resources != nil {
+		for _, r := range pt.Resources.Inputs {
+			for _, name := range r.From {
+				names[name] = true
+			}
+		}
+	}
+
+	var answer []string
+	for name := range names {
+		answer = append(answer, name)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// EvaluateTask evaluates the when expressions of the given pipeline task.
+// A task runs if all of its when expressions match
+func EvaluateTask(pt *tektonv1beta1.PipelineTask, values map[string]string) *TaskResult {
+	answer := &TaskResult{
+		Name:   pt.Name,
+		Status: StatusRun,
+	}
+	for i := range pt.WhenExpressions {
+		w := EvaluateWhen(&pt.WhenExpressions[i], values)
+		answer.Whens = append(answer.Whens, w)
+
+		switch {
+		case answer.Status == StatusSkip:
+		case len(w.Unresolved) > 0:
+			answer.Status = StatusUnknown
+		case !w.Matched:
+			answer.Status = StatusSkip
+		}
+	}
+	return answer
+}
+
+// EvaluateWhen evaluates a single when expression substituting any variables from the given values
+func EvaluateWhen(we *tektonv1beta1.WhenExpression, values map[string]string) *WhenResult {
+	unresolved := map[string]bool{}
+	answer := &WhenResult{
+		Input:    substitute(we.Input, values, unresolved),
+		Operator: string(we.Operator),
+	}
+	for _, v := range we.Values {
+		answer.Values = append(answer.Values, substitute(v, values, unresolved))
+	}
+	for k := range unresolved {
+		answer.Unresolved = append(answer.Unresolved, k)
+	}
+	sort.Strings(answer.Unresolved)
+
+	found := false
+	for _, v := range answer.Values {
+		if v == answer.Input {
+			found = true
+			break
+		}
+	}
+	switch we.Operator {
+	case selection.In:
+		answer.Matched = found
+	case selection.NotIn:
+		answer.Matched = !found
+	}
+	return answer
+}
+
+// substitute replaces any $(name) expressions with the values, recording any missing variables
+func substitute(text string, values map[string]string, unresolved map[string]bool) string {
+	return variableRegex.ReplaceAllStringFunc(text, func(expression string) string {
+		name := strings.TrimSpace(expression[2 : len(expression)-1])
+		value, ok := values[name]
+		if !ok {
+			unresolved[name] = true
+			return expression
+		}
+		return value
+	})
+}
@@ -0,0 +1,76 @@
+package eval_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   []string
+		values   []string
+		expected map[string]string
+		parents  map[string]string
+	}{
+		{
+			name: "defaults",
+			expected: map[string]string{
+				"from-build-pack":   eval.StatusRun,
+				"promote":           eval.StatusRun,
+				"deploy":            eval.StatusSkip,
+				"changelog":         eval.StatusUnknown,
+				"release-notes":     eval.StatusRun,
+				"notify":            eval.StatusSkip,
+				"publish-changelog": eval.StatusUnknown,
+			},
+			parents: map[string]string{
+				"notify":            "deploy",
+				"publish-changelog": "changelog",
+			},
+		},
+		{
+			name:   "overrides",
+			params: []string{"PULL_BASE_REF=feature", "DEPLOY=true"},
+			values: []string{"tasks.from-build-pack.results.changed=true"},
+			expected: map[string]string{
+				"from-build-pack":   eval.StatusRun,
+				"promote":           eval.StatusSkip,
+				"deploy":            eval.StatusRun,
+				"changelog":         eval.StatusRun,
+				"release-notes":     eval.StatusSkip,
+				"notify":            eval.StatusRun,
+				"publish-changelog": eval.StatusRun,
+			},
+			parents: map[string]string{
+				"release-notes": "promote",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		_, o := eval.NewCmdPipelineEval()
+
+		buf := &bytes.Buffer{}
+		o.File = filepath.Join("test_data", "release.yaml")
+		o.Params = tc.params
+		o.Values = tc.values
+		o.Out = buf
+		o.Ctx = context.TODO()
+		err := o.Run()
+		require.NoError(t, err, "failed to run eval for %s", tc.name)
+
+		require.Len(t, o.Results, len(tc.expected), "results for %s", tc.name)
+		for _, r := range o.Results {
+			assert.Equal(t, tc.expected[r.Name], r.Status, "status of task %s for %s", r.Name, tc.name)
+			assert.Equal(t, tc.parents[r.Name], r.Parent, "parent of task %s for %s", r.Name, tc.name)
+		}
+		t.Logf("%s results:\n%s\n", tc.name, buf.String())
+	}
+}
@@ -0,0 +1,237 @@
+package exportconfig
+
+import (
+	"os"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/clusterconfig"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Dir                      string
+	Namespace                string
+	ConfigMaps               []string
+	CronJobs                 []string
+	IgnoreSourceRepositories bool
+	KubeClient               kubernetes.Interface
+	JXClient                 versioned.Interface
+	ExportedFiles            []string
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Exports the pipeline related configuration in the cluster to a directory so that it can be committed to the cluster GitOps repository.
+
+		The lighthouse configuration, which contains the trigger mappings and the keeper and max concurrency settings used to throttle pipelines, the lighthouse plugins configuration, any additional ConfigMaps, the garbage collection CronJobs which define the retention policies and the SourceRepository resources are exported.
+		They can be restored via 'jx pipeline import-config'
+`)
+
+	cmdExample = templates.Examples(`
+		# Exports the pipeline configuration to the config-root folder of the cluster GitOps repository
+		jx pipeline export-config --dir config-root
+
+		# Exports the pipeline configuration including an additional ConfigMap
+		jx pipeline export-config --dir config-root --configmap config --configmap plugins --configmap lighthouse-keeper
+	`)
+)
+
+// NewCmdPipelineExportConfig creates the command
+func NewCmdPipelineExportConfig() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "export-config",
+		Short:   "Exports the pipeline related configuration in the cluster to a directory",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"export"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "The directory to export the configuration into")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The kubernetes namespace to use. If not specified the default namespace is used")
+	cmd.Flags().StringArrayVarP(&o.ConfigMaps, "configmap", "", clusterconfig.DefaultConfigMaps, "The names of the ConfigMaps to export")
+	cmd.Flags().StringArrayVarP(&o.CronJobs, "cronjob", "", clusterconfig.DefaultCronJobs, "The names of the garbage collection CronJobs to export which define the retention policies of pipelines")
+	cmd.Flags().BoolVarP(&o.IgnoreSourceRepositories, "ignore-source-repositories", "", false, "Do not export the SourceRepository resources")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create kube client")
+	}
+	if !o.IgnoreSourceRepositories {
+		o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the jx client")
+		}
+	}
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	if len(o.ConfigMaps) == 0 {
+		o.ConfigMaps = clusterconfig.DefaultConfigMaps
+	}
+	if len(o.CronJobs) == 0 {
+		o.CronJobs = clusterconfig.DefaultCronJobs
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	ns := o.Namespace
+	dir := clusterconfig.NamespaceDir(o.Dir, ns)
+	err = os.MkdirAll(dir, files.DefaultDirWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+
+	err = o.exportConfigMaps(ns)
+	if err != nil {
+		return err
+	}
+	err = o.exportCronJobs(ns)
+	if err != nil {
+		return err
+	}
+	if !o.IgnoreSourceRepositories {
+		err = o.exportSourceRepositories(ns)
+		if err != nil {
+			return err
+		}
+	}
+	log.Logger().Infof("exported %d files to %s", len(o.ExportedFiles), info(o.Dir))
+	return nil
+}
+
+func (o *Options) exportConfigMaps(ns string) error {
+	ctx := o.GetContext()
+	for _, name := range o.ConfigMaps {
+		cm, err := o.KubeClient.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Logger().Warnf("no ConfigMap %s exists in namespace %s", name, ns)
+				continue
+			}
+			return errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", name, ns)
+		}
+
+		exported := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: clusterconfig.ToExportedObjectMeta(&cm.ObjectMeta),
+			Data:       cm.Data,
+			BinaryData: cm.BinaryData,
+		}
+		path := clusterconfig.ConfigMapPath(o.Dir, ns, name)
+		err = o.saveFile(exported, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) exportCronJobs(ns string) error {
+	ctx := o.GetContext()
+	for _, name := range o.CronJobs {
+		cj, err := o.KubeClient.BatchV1beta1().CronJobs(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Logger().Warnf("no CronJob %s exists in namespace %s", name, ns)
+				continue
+			}
+			return errors.Wrapf(err, "failed to find CronJob %s in namespace %s", name, ns)
+		}
+
+		exported := &batchv1beta1.CronJob{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "batch/v1beta1",
+				Kind:       "CronJob",
+			},
+			ObjectMeta: clusterconfig.ToExportedObjectMeta(&cj.ObjectMeta),
+			Spec:       cj.Spec,
+		}
+		path := clusterconfig.CronJobPath(o.Dir, ns, name)
+		err = o.saveFile(exported, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) exportSourceRepositories(ns string) error {
+	ctx := o.GetContext()
+	srList, err := o.JXClient.JenkinsV1().SourceRepositories(ns).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to list SourceRepository resources in namespace %s", ns)
+	}
+	if srList == nil {
+		return nil
+	}
+	for i := range srList.Items {
+		sr := &srList.Items[i]
+		exported := &v1.SourceRepository{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "jenkins.io/v1",
+				Kind:       "SourceRepository",
+			},
+			ObjectMeta: clusterconfig.ToExportedObjectMeta(&sr.ObjectMeta),
+			Spec:       sr.Spec,
+		}
+		path := clusterconfig.SourceRepositoryPath(o.Dir, ns, sr.Name)
+		err = o.saveFile(exported, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) saveFile(resource interface{}, path string) error {
+	err := yamls.SaveFile(resource, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	o.ExportedFiles = append(o.ExportedFiles, path)
+	log.Logger().Debugf("saved file %s", path)
+	return nil
+}
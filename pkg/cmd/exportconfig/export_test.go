@@ -0,0 +1,123 @@
+package exportconfig_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/clusterconfig"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/exportconfig"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/importconfig"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExportAndImportConfig(t *testing.T) {
+	ns := "jx"
+	configYAML := "in_repo_config:\n  enabled:\n    myorg/myrepo: true\n"
+	gcArgs := []string{"gc", "activities", "--release-history-limit", "5", "--pr-history-limit", "2"}
+
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	_, exporter := exportconfig.NewCmdPipelineExportConfig()
+	exporter.Dir = tmpDir
+	exporter.Namespace = ns
+	exporter.Ctx = context.TODO()
+	exporter.KubeClient = fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "config",
+				Namespace:       ns,
+				ResourceVersion: "123",
+				Annotations: map[string]string{
+					clusterconfig.LastAppliedAnnotation: "{}",
+				},
+			},
+			Data: map[string]string{
+				"config.yaml": configYAML,
+			},
+		},
+		&batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "jx-gcactivities",
+				Namespace:       ns,
+				ResourceVersion: "456",
+			},
+			Spec: batchv1beta1.CronJobSpec{
+				Schedule: "0/30 */3 * * *",
+				JobTemplate: batchv1beta1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name:  "gcactivities",
+										Image: "ghcr.io/jenkins-x/jx-boot",
+										Args:  gcArgs,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	exporter.JXClient = fakejx.NewSimpleClientset(
+		&v1.SourceRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myorg-myrepo",
+				Namespace: ns,
+			},
+			Spec: v1.SourceRepositorySpec{
+				Org:          "myorg",
+				Repo:         "myrepo",
+				Provider:     "https://github.com",
+				ProviderKind: "github",
+			},
+		},
+	)
+	err = exporter.Run()
+	require.NoError(t, err, "failed to export config")
+
+	assert.Equal(t, []string{
+		clusterconfig.ConfigMapPath(tmpDir, ns, "config"),
+		clusterconfig.CronJobPath(tmpDir, ns, "jx-gcactivities"),
+		clusterconfig.SourceRepositoryPath(tmpDir, ns, "myorg-myrepo"),
+	}, exporter.ExportedFiles, "exported files")
+
+	_, importer := importconfig.NewCmdPipelineImportConfig()
+	importer.Dir = tmpDir
+	importer.Namespace = ns
+	importer.Ctx = context.TODO()
+	importer.KubeClient = fake.NewSimpleClientset()
+	importer.JXClient = fakejx.NewSimpleClientset()
+	err = importer.Run()
+	require.NoError(t, err, "failed to import config")
+
+	assert.Len(t, importer.ImportedObjects, 3, "imported objects")
+
+	ctx := context.TODO()
+	cm, err := importer.KubeClient.CoreV1().ConfigMaps(ns).Get(ctx, "config", metav1.GetOptions{})
+	require.NoError(t, err, "failed to find imported ConfigMap")
+	assert.Equal(t, configYAML, cm.Data["config.yaml"], "imported ConfigMap data")
+	assert.Empty(t, cm.Annotations[clusterconfig.LastAppliedAnnotation], "imported ConfigMap should not have the last applied annotation")
+
+	cj, err := importer.KubeClient.BatchV1beta1().CronJobs(ns).Get(ctx, "jx-gcactivities", metav1.GetOptions{})
+	require.NoError(t, err, "failed to find imported CronJob")
+	assert.Equal(t, "0/30 */3 * * *", cj.Spec.Schedule, "imported CronJob schedule")
+	require.Len(t, cj.Spec.JobTemplate.Spec.Template.Spec.Containers, 1, "imported CronJob containers")
+	assert.Equal(t, gcArgs, cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Args, "imported CronJob retention arguments")
+
+	sr, err := importer.JXClient.JenkinsV1().SourceRepositories(ns).Get(ctx, "myorg-myrepo", metav1.GetOptions{})
+	require.NoError(t, err, "failed to find imported SourceRepository")
+	assert.Equal(t, "myrepo", sr.Spec.Repo, "imported SourceRepository repo")
+}
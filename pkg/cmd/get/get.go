@@ -10,6 +10,8 @@ import (
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/activities"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/constants"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/triggers"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
@@ -131,12 +133,12 @@ func (o *Options) renderPostsubmits(ctx context.Context) error {
 
 	out := os.Stdout
 	t := table.CreateTable(out)
-	t.AddRow("REPOSITORY", "NAME", "BRANCHES")
+	t.AddRow("REPOSITORY", "NAME", "BRANCHES", "OWNER")
 
 	for repo, submits := range cfg.Postsubmits {
 		for i := range submits {
 			submit := &submits[i]
-			t.AddRow(repo, submit.Name, strings.Join(submit.Branches, ", "))
+			t.AddRow(repo, submit.Name, strings.Join(submit.Branches, ", "), owners.ToString(submit.Annotations))
 		}
 	}
 	t.Render()
@@ -152,12 +154,12 @@ func (o *Options) renderPresubmits(ctx context.Context) error {
 
 	out := os.Stdout
 	t := table.CreateTable(out)
-	t.AddRow("REPOSITORY", "CONTEXT", "RERUN COMMAND")
+	t.AddRow("REPOSITORY", "CONTEXT", "RERUN COMMAND", "OWNER")
 
 	for repo, submits := range cfg.Presubmits {
 		for i := range submits {
 			submit := &submits[i]
-			t.AddRow(repo, submit.Name, submit.RerunCommand)
+			t.AddRow(repo, submit.Name, submit.RerunCommand, owners.ToString(submit.Annotations))
 		}
 	}
 	t.Render()
@@ -229,10 +231,11 @@ func (o *Options) renderPipelineRuns(ctx context.Context) error {
 	}
 
 	t := table.CreateTable(out)
-	t.AddRow("Name", "URL", "LAST_BUILD", "STATUS", "DURATION")
+	t.AddRow("Name", "URL", "LAST_BUILD", "STATUS", "DURATION", "SKIPPED", "OWNER")
 
 	for _, j := range names {
-		t.AddRow(j, "N/A", "N/A", "N/A", "N/A")
+		skipped := pipelines.SkippedStageNames(pipelines.SkippedStages(m[j]))
+		t.AddRow(j, "N/A", "N/A", "N/A", "N/A", strings.Join(skipped, ", "), owners.ToString(m[j].Annotations))
 	}
 	t.Render()
 	return nil
@@ -1,12 +1,16 @@
 package getlog
 
 import (
+	"context"
 	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/hooks"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/logrelay"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/input"
@@ -15,15 +19,18 @@ import (
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
 	"github.com/jenkins-x/jx-kube-client/v3/pkg/kubeclient"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/cenkalti/backoff"
 
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 )
 
@@ -32,6 +39,7 @@ type Options struct {
 	options.BaseOptions
 
 	ScmDiscover             scmhelpers.Options
+	Hooks                   hooks.Options
 	Args                    []string
 	Format                  string
 	Namespace               string
@@ -41,6 +49,7 @@ type Options struct {
 	FailIfPodFails          bool
 	WaitForPipelineDuration time.Duration
 	BuildFilter             tektonlog.BuildPodInfoFilter
+	Relay                   logrelay.ClientOptions
 	KubeClient              kubernetes.Interface
 	JXClient                versioned.Interface
 	TektonClient            tektonclient.Interface
@@ -77,6 +86,9 @@ var (
 
 		# View the build logs for a specific tekton build pod
 		jx pipeline log --pod my-pod-name
+
+		# View the build logs via the log relay running in the cluster
+		jx pipeline log --repo cheese --relay jx-pipeline-log-relay.example.com:443 --relay-token mytoken
 	`)
 )
 
@@ -102,9 +114,13 @@ func NewCmdGetBuildLogs() (*cobra.Command, *Options) {
 	cmd.Flags().BoolVarP(&o.FailIfPodFails, "fail-with-pod", "", false, "Return an error if the pod fails")
 	cmd.Flags().DurationVarP(&o.WaitForPipelineDuration, "wait-duration", "d", time.Minute*20, "Timeout period waiting for the given pipeline to be created")
 	cmd.Flags().BoolVarP(&o.CurrentFolder, "current", "c", false, "Display logs using current folder as repo name, and parent folder as owner")
+	cmd.Flags().StringVarP(&o.Relay.Address, "relay", "", "", "The host:port of the log relay running in the cluster. If specified the logs are streamed via the relay rather than from the pods")
+	cmd.Flags().StringVarP(&o.Relay.Token, "relay-token", "", "", "The token to authenticate with the log relay. Defaults to the $"+logrelay.TokenEnvVar+" environment variable")
+	cmd.Flags().BoolVarP(&o.Relay.Insecure, "relay-insecure", "", false, "Connects to the log relay without TLS")
 
 	o.BaseOptions.AddBaseFlags(cmd)
 	o.BuildFilter.AddFlags(cmd)
+	o.Hooks.AddFlags(cmd)
 	return cmd, o
 }
 
@@ -117,6 +133,15 @@ func (o *Options) Validate() error {
 	if err != nil {
 		return err
 	}
+	if o.Input == nil {
+		o.Input = inputfactory.NewInput(&o.BaseOptions)
+	}
+	if o.Relay.Address != "" {
+		if o.Relay.Token == "" {
+			o.Relay.Token = os.Getenv(logrelay.TokenEnvVar)
+		}
+		return nil
+	}
 
 	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
 	if err != nil {
@@ -127,10 +152,6 @@ func (o *Options) Validate() error {
 		return errors.Wrapf(err, "failed to create the jx client")
 	}
 
-	if o.Input == nil {
-		o.Input = inputfactory.NewInput(&o.BaseOptions)
-	}
-
 	if o.TektonClient == nil {
 		f := kubeclient.NewFactory()
 		cfg, err := f.CreateKubeConfig()
@@ -152,6 +173,9 @@ func (o *Options) Run() error {
 		return errors.Wrapf(err, "failed to validate options")
 	}
 
+	if o.Relay.Address != "" {
+		return o.getRelayLog()
+	}
 	return o.getPipelineLog(o.KubeClient, o.TektonClient, o.JXClient, o.Namespace)
 }
 
@@ -252,5 +276,102 @@ func (o *Options) getTektonLogs() (bool, error) {
 		return true, errors.New("there are no build logs for the supplied filters")
 	}
 
-	return false, o.TektonLogger.GetLogsForActivity(ctx, o.Out, pa, name, prList)
+	err = o.TektonLogger.GetLogsForActivity(ctx, o.Out, pa, name, prList)
+	if err != nil {
+		return false, err
+	}
+	return false, o.runPostRunHooks(ctx, pa, prList)
+}
+
+// runPostRunHooks invokes the post-run hook for each of the PipelineRuns of the build which have completed
+func (o *Options) runPostRunHooks(ctx context.Context, pa *v1.PipelineActivity, prList []*tektonv1beta1.PipelineRun) error {
+	t := o.TektonLogger
+	for _, pr := range prList {
+		latest, err := t.TektonClient.TektonV1beta1().PipelineRuns(t.Namespace).Get(ctx, pr.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get PipelineRun %s in namespace %s", pr.Name, t.Namespace)
+		}
+		if !tektonlog.PipelineRunIsComplete(latest) {
+			log.Logger().Debugf("not running the %s hook as PipelineRun %s has not completed", hooks.PostRun, latest.Name)
+			continue
+		}
+		ps := &pa.Spec
+		err = o.Hooks.Run(&hooks.Event{
+			Hook:        hooks.PostRun,
+			Namespace:   t.Namespace,
+			Owner:       ps.GitOwner,
+			Repository:  ps.GitRepository,
+			Branch:      ps.GitBranch,
+			Context:     ps.Context,
+			Status:      hooks.PipelineRunStatus(latest),
+			PipelineRun: latest,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to run hooks after PipelineRun %s completed", latest.Name)
+		}
+	}
+	return nil
+}
+
+// getRelayLog prompts the user, if needed, to choose a pipeline then streams its log via the log relay
+func (o *Options) getRelayLog() error {
+	ctx := o.GetContext()
+	if o.CurrentFolder {
+		err := o.ScmDiscover.Validate()
+		if err != nil {
+			return errors.Wrapf(err, "failed to discover current git repository in dir %s", o.ScmDiscover.Dir)
+		}
+		o.BuildFilter.Repository = o.ScmDiscover.Repository
+		o.BuildFilter.Owner = o.ScmDiscover.Owner
+	}
+
+	client, err := logrelay.NewClient(ctx, &o.Relay)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	filter := o.BuildFilter
+	if len(o.Args) > 0 {
+		filter.Filter = o.Args[0]
+	}
+	var name string
+	f := func() error {
+		name, err = o.pickRelayBuild(ctx, client, &filter)
+		return err
+	}
+	err = f()
+	if err != nil && o.Wait {
+		log.Logger().Info("The selected pipeline didn't start, let's wait a bit")
+		err = Retry(o.WaitForPipelineDuration, f)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Logger().Infof("Build logs for %s", termcolor.ColorInfo(name))
+	return client.StreamLogs(ctx, &logrelay.LogsRequest{
+		Name:           name,
+		FailIfPodFails: o.FailIfPodFails,
+	}, o.Out)
+}
+
+func (o *Options) pickRelayBuild(ctx context.Context, client *logrelay.Client, filter *tektonlog.BuildPodInfoFilter) (string, error) {
+	resp, err := client.ListBuilds(ctx, &logrelay.BuildsRequest{Filter: *filter})
+	if err != nil {
+		return "", err
+	}
+	names := resp.Names
+	if len(names) == 0 {
+		return "", errors.New("there are no build logs for the supplied filters")
+	}
+
+	defaultName := ""
+	if o.BatchMode {
+		defaultName = names[0]
+		if len(names) > 1 {
+			log.Logger().Warnf("more than one pipeline returned in batch mode so will pick the first one: %s", defaultName)
+		}
+	}
+	return o.Input.PickNameWithDefault(names, "Which build do you want to view the logs of?: ", defaultName, "")
 }
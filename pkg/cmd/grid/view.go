@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/table"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
@@ -19,7 +21,7 @@ func (m model) View() string {
 
 	s := &strings.Builder{}
 	t := table.CreateTable(s)
-	t.AddRow("REPOSITORY", "BRANCH", "BUILD", "CONTEXT", "STATUS", "LAST STEP")
+	t.AddRow("REPOSITORY", "BRANCH", "BUILD", "CONTEXT", "STATUS", "SKIPPED", "OWNER", "LAST STEP")
 
 	for i, name := range m.activityTable.names {
 		if i >= m.activityTable.height {
@@ -36,7 +38,7 @@ func (m model) View() string {
 		if i == m.activityTable.current {
 			repo = termcolor.ColorStatus(repo)
 		}
-		t.AddRow(repo, as.GitBranch, as.Build, as.Context, ToPipelineStatus(as.Status), ToLastStep(act))
+		t.AddRow(repo, as.GitBranch, as.Build, as.Context, ToPipelineStatus(as.Status), strings.Join(pipelines.ActivitySkippedStageNames(act), ", "), owners.ToString(act.Annotations), ToLastStep(act))
 	}
 
 	t.Render()
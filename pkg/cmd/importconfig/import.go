@@ -0,0 +1,291 @@
+package importconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/clusterconfig"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+	DryRun dryrun.Options
+
+	Dir             string
+	Namespace       string
+	KubeClient      kubernetes.Interface
+	JXClient        versioned.Interface
+	ImportedObjects []string
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Imports the pipeline related configuration previously exported via 'jx pipeline export-config' into the cluster.
+
+		This is useful for disaster recovery. Any existing resources are updated.
+`)
+
+	cmdExample = templates.Examples(`
+		# Imports the pipeline configuration from the config-root folder of the cluster GitOps repository
+		jx pipeline import-config --dir config-root
+
+		# Displays the changes which would be made to the cluster without applying them
+		jx pipeline import-config --dir config-root --dry-run --diff
+	`)
+)
+
+// NewCmdPipelineImportConfig creates the command
+func NewCmdPipelineImportConfig() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "import-config",
+		Short:   "Imports the pipeline related configuration from a directory into the cluster",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "The directory containing the exported configuration")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The kubernetes namespace to import into. If not specified the default namespace is used")
+
+	o.DryRun.AddFlags(cmd)
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	err := o.DryRun.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "invalid dry run options")
+	}
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create kube client")
+	}
+	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the jx client")
+	}
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	dir := clusterconfig.NamespaceDir(o.Dir, o.Namespace)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info == nil || info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		switch {
+		case strings.HasSuffix(name, "-cm.yaml"):
+			return o.importConfigMap(path)
+		case strings.HasSuffix(name, "-cronjob.yaml"):
+			return o.importCronJob(path)
+		case strings.HasSuffix(name, "-sr.yaml"):
+			return o.importSourceRepository(path)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to import configuration from dir %s", dir)
+	}
+	if o.DryRun.IsDryRun() {
+		log.Logger().Infof("would import %d resources into namespace %s", len(o.ImportedObjects), info(o.Namespace))
+		return nil
+	}
+	log.Logger().Infof("imported %d resources into namespace %s", len(o.ImportedObjects), info(o.Namespace))
+	return nil
+}
+
+func (o *Options) importConfigMap(path string) error {
+	ctx := o.GetContext()
+	ns := o.Namespace
+	cm := &corev1.ConfigMap{}
+	err := yamls.LoadFile(path, cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+	cm.Namespace = ns
+
+	configMaps := o.KubeClient.CoreV1().ConfigMaps(ns)
+	existing, err := configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", cm.Name, ns)
+		}
+		created := cm
+		if !o.DryRun.IsClient() {
+			created, err = configMaps.Create(ctx, cm, o.DryRun.CreateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to create ConfigMap %s in namespace %s", cm.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Create, "ConfigMap", cm.Name, nil, created)
+		if err != nil {
+			return err
+		}
+	} else {
+		current := existing.DeepCopy()
+		existing.Labels = cm.Labels
+		existing.Annotations = cm.Annotations
+		existing.Data = cm.Data
+		existing.BinaryData = cm.BinaryData
+		updated := existing
+		if !o.DryRun.IsClient() {
+			updated, err = configMaps.Update(ctx, existing, o.DryRun.UpdateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to update ConfigMap %s in namespace %s", cm.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Update, "ConfigMap", cm.Name, current, updated)
+		if err != nil {
+			return err
+		}
+	}
+	o.ImportedObjects = append(o.ImportedObjects, "ConfigMap/"+cm.Name)
+	return nil
+}
+
+func (o *Options) importCronJob(path string) error {
+	ctx := o.GetContext()
+	ns := o.Namespace
+	cj := &batchv1beta1.CronJob{}
+	err := yamls.LoadFile(path, cj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+	cj.Namespace = ns
+
+	cronJobs := o.KubeClient.BatchV1beta1().CronJobs(ns)
+	existing, err := cronJobs.Get(ctx, cj.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to find CronJob %s in namespace %s", cj.Name, ns)
+		}
+		created := cj
+		if !o.DryRun.IsClient() {
+			created, err = cronJobs.Create(ctx, cj, o.DryRun.CreateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to create CronJob %s in namespace %s", cj.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Create, "CronJob", cj.Name, nil, created)
+		if err != nil {
+			return err
+		}
+	} else {
+		current := existing.DeepCopy()
+		existing.Labels = cj.Labels
+		existing.Annotations = cj.Annotations
+		existing.Spec = cj.Spec
+		updated := existing
+		if !o.DryRun.IsClient() {
+			updated, err = cronJobs.Update(ctx, existing, o.DryRun.UpdateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to update CronJob %s in namespace %s", cj.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Update, "CronJob", cj.Name, current, updated)
+		if err != nil {
+			return err
+		}
+	}
+	o.ImportedObjects = append(o.ImportedObjects, "CronJob/"+cj.Name)
+	return nil
+}
+
+func (o *Options) importSourceRepository(path string) error {
+	ctx := o.GetContext()
+	ns := o.Namespace
+	sr := &v1.SourceRepository{}
+	err := yamls.LoadFile(path, sr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+	sr.Namespace = ns
+
+	srInterface := o.JXClient.JenkinsV1().SourceRepositories(ns)
+	existing, err := srInterface.Get(ctx, sr.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to find SourceRepository %s in namespace %s", sr.Name, ns)
+		}
+		created := sr
+		if !o.DryRun.IsClient() {
+			created, err = srInterface.Create(ctx, sr, o.DryRun.CreateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to create SourceRepository %s in namespace %s", sr.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Create, "SourceRepository", sr.Name, nil, created)
+		if err != nil {
+			return err
+		}
+	} else {
+		current := existing.DeepCopy()
+		existing.Labels = sr.Labels
+		existing.Annotations = sr.Annotations
+		existing.Spec = sr.Spec
+		updated := existing
+		if !o.DryRun.IsClient() {
+			updated, err = srInterface.Update(ctx, existing, o.DryRun.UpdateOptions())
+			if err != nil {
+				return errors.Wrapf(err, "failed to update SourceRepository %s in namespace %s", sr.Name, ns)
+			}
+		}
+		err = o.report(dryrun.Update, "SourceRepository", sr.Name, current, updated)
+		if err != nil {
+			return err
+		}
+	}
+	o.ImportedObjects = append(o.ImportedObjects, "SourceRepository/"+sr.Name)
+	return nil
+}
+
+func (o *Options) report(action, kind, name string, oldResource, newResource interface{}) error {
+	ns := o.Namespace
+	if o.DryRun.IsDryRun() {
+		return o.DryRun.Report(action, kind, name, ns, oldResource, newResource)
+	}
+	log.Logger().Infof("%sd %s %s in namespace %s", action, kind, info(name), info(ns))
+	return o.DryRun.PrintDiff(kind, name, oldResource, newResource)
+}
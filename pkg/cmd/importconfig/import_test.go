@@ -0,0 +1,132 @@
+package importconfig_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/clusterconfig"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/importconfig"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestImportConfigDryRun(t *testing.T) {
+	ns := "jx"
+
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	defer os.RemoveAll(tmpDir)
+
+	err = os.MkdirAll(clusterconfig.NamespaceDir(tmpDir, ns), os.ModePerm)
+	require.NoError(t, err, "failed to create namespace dir")
+	for name, value := range map[string]string{"config": "new config", "plugins": "new plugins"} {
+		cm := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Data: map[string]string{
+				name + ".yaml": value,
+			},
+		}
+		path := clusterconfig.ConfigMapPath(tmpDir, ns, name)
+		err = yamls.SaveFile(cm, path)
+		require.NoError(t, err, "failed to save %s", path)
+	}
+
+	for _, dryRun := range []string{dryrun.Client, dryrun.Server} {
+		_, o := importconfig.NewCmdPipelineImportConfig()
+		o.DryRun.DryRun = dryRun
+		o.DryRun.Out = &bytes.Buffer{}
+		o.Dir = tmpDir
+		o.Namespace = ns
+		o.Ctx = context.Background()
+		kubeClient := &recordingKubeClient{
+			Interface: fake.NewSimpleClientset(
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "config",
+						Namespace: ns,
+					},
+					Data: map[string]string{
+						"config.yaml": "old config",
+					},
+				},
+			),
+		}
+		o.KubeClient = kubeClient
+		o.JXClient = fakejx.NewSimpleClientset()
+
+		err = o.Run()
+		require.NoError(t, err, "failed to import config for dry run %s", dryRun)
+		assert.Len(t, o.ImportedObjects, 2, "imported objects for dry run %s", dryRun)
+
+		if dryRun == dryrun.Client {
+			assert.Empty(t, kubeClient.creates, "should not have sent any requests to create a ConfigMap for dry run %s", dryRun)
+			assert.Empty(t, kubeClient.updates, "should not have sent any requests to update a ConfigMap for dry run %s", dryRun)
+
+			configMaps := kubeClient.Interface.CoreV1().ConfigMaps(ns)
+			cm, err := configMaps.Get(o.Ctx, "config", metav1.GetOptions{})
+			require.NoError(t, err, "failed to get ConfigMap config")
+			assert.Equal(t, "old config", cm.Data["config.yaml"], "should not have updated the ConfigMap for dry run %s", dryRun)
+
+			cmList, err := configMaps.List(o.Ctx, metav1.ListOptions{})
+			require.NoError(t, err, "failed to list ConfigMaps")
+			assert.Len(t, cmList.Items, 1, "should not have created a ConfigMap for dry run %s", dryRun)
+			continue
+		}
+		require.Len(t, kubeClient.creates, 1, "should have sent a request to create the plugins ConfigMap for dry run %s", dryRun)
+		assert.Equal(t, []string{metav1.DryRunAll}, kubeClient.creates[0].DryRun, "create options for dry run %s", dryRun)
+		require.Len(t, kubeClient.updates, 1, "should have sent a request to update the config ConfigMap for dry run %s", dryRun)
+		assert.Equal(t, []string{metav1.DryRunAll}, kubeClient.updates[0].DryRun, "update options for dry run %s", dryRun)
+	}
+}
+
+// recordingKubeClient records the options used to create and update ConfigMaps as the fake clients ignore them
+type recordingKubeClient struct {
+	kubernetes.Interface
+	creates []metav1.CreateOptions
+	updates []metav1.UpdateOptions
+}
+
+func (c *recordingKubeClient) CoreV1() typedcorev1.CoreV1Interface {
+	return &recordingCoreV1{CoreV1Interface: c.Interface.CoreV1(), client: c}
+}
+
+type recordingCoreV1 struct {
+	typedcorev1.CoreV1Interface
+	client *recordingKubeClient
+}
+
+func (c *recordingCoreV1) ConfigMaps(ns string) typedcorev1.ConfigMapInterface {
+	return &recordingConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(ns), client: c.client}
+}
+
+type recordingConfigMaps struct {
+	typedcorev1.ConfigMapInterface
+	client *recordingKubeClient
+}
+
+func (c *recordingConfigMaps) Create(ctx context.Context, cm *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	c.client.creates = append(c.client.creates, opts)
+	return c.ConfigMapInterface.Create(ctx, cm, opts)
+}
+
+func (c *recordingConfigMaps) Update(ctx context.Context, cm *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	c.client.updates = append(c.client.updates, opts)
+	return c.ConfigMapInterface.Update(ctx, cm, opts)
+}
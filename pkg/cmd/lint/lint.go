@@ -2,7 +2,6 @@ package lint
 
 import (
 	"context"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -15,6 +14,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
@@ -187,41 +187,14 @@ func (o *Options) ProcessFile(path string) error {
 	return nil
 }
 
+// ValidatePipelineRun validates the PipelineRun and the volumes of its tasks
 func ValidatePipelineRun(ctx context.Context, pr *v1beta1.PipelineRun) *apis.FieldError {
-	err := pr.Validate(ctx)
-	if err != nil {
-		return err
-	}
-
-	// lets validate each TaskSpec has the right volumes etc
-	ps := pr.Spec.PipelineSpec
-	if ps == nil {
-		return nil
-	}
-	for i := range ps.Tasks {
-		pt := &ps.Tasks[i]
-		if pt.TaskSpec == nil {
-			continue
-		}
-		err = err.Also(ValidateTaskRunVolumesExist(&pt.TaskSpec.TaskSpec).ViaFieldIndex("tasks", i)).ViaField("spec", "pipelineSpec")
-	}
-	return err
+	return pipelines.ValidatePipelineRun(ctx, pr)
 }
 
-func ValidateTaskRunVolumesExist(ts *v1beta1.TaskSpec) (errs *apis.FieldError) {
-	volumeNames := map[string]bool{}
-	for _, v := range ts.Volumes {
-		volumeNames[v.Name] = true
-	}
-
-	for i, s := range ts.Steps {
-		for j, v := range s.VolumeMounts {
-			if !volumeNames[v.Name] {
-				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Not found: %s", v.Name), "name").ViaFieldIndex("volumeMounts", j).ViaFieldIndex("steps", i).ViaField("taskSpec"))
-			}
-		}
-	}
-	return
+// ValidateTaskRunVolumesExist validates the volume mounts of the steps refer to volumes of the task
+func ValidateTaskRunVolumesExist(ts *v1beta1.TaskSpec) *apis.FieldError {
+	return pipelines.ValidateTaskRunVolumesExist(ts)
 }
 
 func (o *Options) ProcessDir(dir string) error {
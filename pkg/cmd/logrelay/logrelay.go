@@ -0,0 +1,164 @@
+package logrelay
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/logrelay"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-kube-client/v3/pkg/kubeclient"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Port         int
+	Namespace    string
+	TokenFile    string
+	TLSCertFile  string
+	TLSKeyFile   string
+	Insecure     bool
+	KubeClient   kubernetes.Interface
+	JXClient     versioned.Interface
+	TektonClient tektonclient.Interface
+	Server       *logrelay.Server
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Runs the log relay inside the cluster which streams build logs to 'jx pipeline log --relay' over a single authenticated gRPC connection.
+
+		This avoids port forwarding and direct pod log access from laptops which can be unreliable through restrictive proxies.
+
+		Clients must present the token in the token file. The token can be defaulted on the client via the ` + logrelay.TokenEnvVar + ` environment variable.
+`)
+
+	cmdExample = templates.Examples(`
+		# Runs the log relay using a token and TLS certificate mounted from Secrets
+		jx pipeline log-relay --token-file /etc/log-relay/token/token --tls-cert-file /etc/log-relay/certs/tls.crt --tls-key-file /etc/log-relay/certs/tls.key
+	`)
+)
+
+// NewCmdPipelineLogRelay creates the command
+func NewCmdPipelineLogRelay() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "log-relay",
+		Short:   "Runs the in cluster relay which streams build logs to clients",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"logs-relay", "relay"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().IntVarP(&o.Port, "port", "p", logrelay.DefaultPort, "The port to listen on")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The kubernetes namespace of the pipelines. If not specified the default namespace is used")
+	cmd.Flags().StringVarP(&o.TokenFile, "token-file", "", "", "The file containing the token clients must present")
+	cmd.Flags().StringVarP(&o.TLSCertFile, "tls-cert-file", "", "", "The TLS certificate file")
+	cmd.Flags().StringVarP(&o.TLSKeyFile, "tls-key-file", "", "", "The TLS private key file")
+	cmd.Flags().BoolVarP(&o.Insecure, "insecure", "", false, "Serves plain text gRPC without TLS such as when TLS is terminated by an ingress")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	if !o.Insecure {
+		if o.TLSCertFile == "" {
+			return options.MissingOption("tls-cert-file")
+		}
+		if o.TLSKeyFile == "" {
+			return options.MissingOption("tls-key-file")
+		}
+	}
+	if o.Server != nil {
+		return nil
+	}
+	token := os.Getenv(logrelay.TokenEnvVar)
+	if o.TokenFile != "" {
+		data, err := ioutil.ReadFile(o.TokenFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read token file %s", o.TokenFile)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return options.MissingOption("token-file")
+	}
+
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create kube client")
+	}
+	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the jx client")
+	}
+	if o.TektonClient == nil {
+		f := kubeclient.NewFactory()
+		cfg, err := f.CreateKubeConfig()
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubernetes config")
+		}
+		o.TektonClient, err = tektonclient.NewForConfig(cfg)
+		if err != nil {
+			return errors.Wrap(err, "error building tekton client")
+		}
+	}
+	o.Server = &logrelay.Server{
+		KubeClient:   o.KubeClient,
+		JXClient:     o.JXClient,
+		TektonClient: o.TektonClient,
+		Namespace:    o.Namespace,
+		Token:        token,
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	var opts []grpc.ServerOption
+	if !o.Insecure {
+		creds, err := credentials.NewServerTLSFromFile(o.TLSCertFile, o.TLSKeyFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load TLS certificate %s", o.TLSCertFile)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", o.Port))
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on port %d", o.Port)
+	}
+	log.Logger().Infof("serving the log relay for namespace %s on port %s", info(o.Server.Namespace), info(o.Port))
+	return o.Server.NewGRPCServer(opts...).Serve(listener)
+}
@@ -0,0 +1,420 @@
+package owners
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/config/job"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Dir             string
+	CodeOwnersFile  string
+	Channel         string
+	TeamChannels    []string
+	Overwrite       bool
+	CodeOwners      *owners.CodeOwners
+	ModifiedFiles   []string
+	teamChannelsMap map[string]string
+}
+
+// triggersConfig a triggers.yaml file including its periodics so they are annotated too
+type triggersConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec triggersSpec `json:"spec"`
+}
+
+type triggersSpec struct {
+	triggerconfig.ConfigSpec `json:",inline"`
+
+	Periodics []job.Periodic `json:"periodics,omitempty"`
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Generates the ownership annotations on the lighthouse triggers from the CODEOWNERS file.
+
+		The owners of each pipeline file are used for the team annotation so that failures can be routed to the right team.
+`)
+
+	cmdExample = templates.Examples(`
+		# Generates the ownership annotations from the CODEOWNERS file
+		jx pipeline owners
+
+		# Generates the ownership annotations with the chat channel for each team
+		jx pipeline owners --team-channel @myorg/frontend=#frontend --channel #builds
+	`)
+)
+
+// NewCmdPipelineOwners creates the command
+func NewCmdPipelineOwners() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "owners",
+		Short:   "Generates the ownership annotations on the lighthouse triggers from the CODEOWNERS file",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"owner", "codeowners"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "The root directory of the repository containing the .lighthouse folder")
+	cmd.Flags().StringVarP(&o.CodeOwnersFile, "codeowners", "", "", "The CODEOWNERS file. If not specified it is found in the usual locations in the repository")
+	cmd.Flags().StringVarP(&o.Channel, "channel", "", "", "The default chat channel for the owners if there is no channel for the team")
+	cmd.Flags().StringArrayVarP(&o.TeamChannels, "team-channel", "", nil, "List of team=channel values to specify the chat channel for each team (can be use multiple times)")
+	cmd.Flags().BoolVarP(&o.Overwrite, "overwrite", "", false, "Overwrites any existing ownership annotations")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies settings
+func (o *Options) Validate() error {
+	err := o.BaseOptions.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate base options")
+	}
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+
+	o.teamChannelsMap = map[string]string{}
+	for _, tc := range o.TeamChannels {
+		paths := strings.SplitN(tc, "=", 2)
+		if len(paths) != 2 {
+			return options.InvalidOptionf("team-channel", tc, "should be of the form 'team=channel'")
+		}
+		o.teamChannelsMap[paths[0]] = paths[1]
+	}
+
+	if o.CodeOwners == nil {
+		if o.CodeOwnersFile == "" {
+			for _, p := range owners.CodeOwnersPaths {
+				path := filepath.Join(o.Dir, p)
+				exists, err := files.FileExists(path)
+				if err != nil {
+					return errors.Wrapf(err, "failed to check if file exists %s", path)
+				}
+				if exists {
+					o.CodeOwnersFile = path
+					break
+				}
+			}
+			if o.CodeOwnersFile == "" {
+				return errors.Errorf("could not find a CODEOWNERS file in dir %s", o.Dir)
+			}
+		}
+		o.CodeOwners, err = owners.LoadCodeOwners(o.CodeOwnersFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load CODEOWNERS")
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	dir := filepath.Join(o.Dir, ".lighthouse")
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info == nil || info.IsDir() || info.Name() != "triggers.yaml" {
+			return nil
+		}
+		return o.processTriggers(path)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to process triggers in dir %s", dir)
+	}
+
+	if len(o.ModifiedFiles) == 0 {
+		log.Logger().Infof("no triggers needed their ownership annotations modifying")
+	}
+	return nil
+}
+
+func (o *Options) processTriggers(path string) error {
+	cfg := &triggersConfig{}
+	err := yamls.LoadFile(path, cfg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	// lets patch the annotations into the lines of the file rather than saving the loaded config so that
+	// any comments, formatting and fields which are not part of the config structs are preserved
+	lines := strings.Split(string(data), "\n")
+	triggerDir := filepath.Dir(path)
+	modified := false
+	annotate := func(section, name, sourcePath string, annotations map[string]string) error {
+		changes := o.annotations(triggerDir, sourcePath, path, annotations)
+		if len(changes) == 0 {
+			return nil
+		}
+		lines, err = patchAnnotations(lines, section, name, changes)
+		if err != nil {
+			return errors.Wrapf(err, "failed to annotate %s %s in %s", section, name, path)
+		}
+		modified = true
+		return nil
+	}
+	for _, r := range cfg.Spec.Presubmits {
+		err = annotate("presubmits", r.Name, r.SourcePath, r.Annotations)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range cfg.Spec.Postsubmits {
+		err = annotate("postsubmits", r.Name, r.SourcePath, r.Annotations)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range cfg.Spec.Periodics {
+		err = annotate("periodics", r.Name, r.SourcePath, r.Annotations)
+		if err != nil {
+			return err
+		}
+	}
+	if !modified {
+		return nil
+	}
+
+	err = ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save %s", path)
+	}
+	o.ModifiedFiles = append(o.ModifiedFiles, path)
+	log.Logger().Infof("modified file %s", info(path))
+	return nil
+}
+
+// annotations returns the ownership annotations of the trigger which need to be added or modified
+func (o *Options) annotations(triggerDir, sourcePath, triggersPath string, annotations map[string]string) map[string]string {
+	path := triggersPath
+	if sourcePath != "" {
+		path = filepath.Join(triggerDir, sourcePath)
+	}
+	rel, err := filepath.Rel(o.Dir, path)
+	if err != nil {
+		rel = path
+	}
+	teams := o.CodeOwners.Owners(filepath.ToSlash(rel))
+	if len(teams) == 0 {
+		log.Logger().Debugf("no owners for %s", rel)
+		return nil
+	}
+
+	channel := o.teamChannelsMap[teams[0]]
+	if channel == "" {
+		channel = o.Channel
+	}
+	values := map[string]string{
+		owners.AnnotationTeam:    strings.Join(teams, ","),
+		owners.AnnotationChannel: channel,
+	}
+
+	changes := map[string]string{}
+	for k, v := range values {
+		if v == "" || annotations[k] == v || (annotations[k] != "" && !o.Overwrite) {
+			continue
+		}
+		changes[k] = v
+	}
+	return changes
+}
+
+// triggerLines the location of a trigger in the lines of a triggers.yaml file
+type triggerLines struct {
+	name            string
+	nameLine        int
+	annotationsLine int
+	fieldIndent     int
+	end             int
+}
+
+// findTrigger finds the lines of the trigger with the given name in the presubmits, postsubmits or periodics section
+func findTrigger(lines []string, section, name string) (*triggerLines, error) {
+	var item *triggerLines
+	var found *triggerLines
+	inSection := false
+	sectionIndent := 0
+	itemIndent := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if !inSection {
+			if strings.HasPrefix(trimmed, section+":") {
+				inSection = true
+				sectionIndent = indent
+			}
+			continue
+		}
+		if indent < sectionIndent || (indent == sectionIndent && !strings.HasPrefix(trimmed, "- ")) {
+			break
+		}
+
+		field := ""
+		if strings.HasPrefix(trimmed, "- ") && (itemIndent < 0 || indent == itemIndent) {
+			// the start of the next trigger
+			if item != nil && item.name == name {
+				found = item
+				break
+			}
+			itemIndent = indent
+			field = strings.TrimLeft(trimmed[2:], " ")
+			item = &triggerLines{
+				nameLine:        -1,
+				annotationsLine: -1,
+				fieldIndent:     indent + len(trimmed) - len(field),
+				end:             len(lines),
+			}
+		} else if item != nil && indent == item.fieldIndent {
+			field = trimmed
+		}
+		if item != nil {
+			item.end = i + 1
+		}
+
+		switch {
+		case strings.HasPrefix(field, "name:"):
+			item.name = yamlValue(field[len("name:"):])
+			item.nameLine = i
+		case strings.HasPrefix(field, "annotations:"):
+			item.annotationsLine = i
+		}
+	}
+	if found == nil && item != nil && item.name == name {
+		found = item
+	}
+	if found == nil || found.nameLine < 0 {
+		return nil, errors.Errorf("could not find the %s trigger %s", section, name)
+	}
+	return found, nil
+}
+
+// patchAnnotations adds or modifies the given annotations of the named trigger in the lines of a triggers.yaml file
+func patchAnnotations(lines []string, section, name string, values map[string]string) ([]string, error) {
+	item, err := findTrigger(lines, section, name)
+	if err != nil {
+		return nil, err
+	}
+	remaining := map[string]string{}
+	for k, v := range values {
+		remaining[k] = v
+	}
+
+	insertAt := item.nameLine + 1
+	childIndent := item.fieldIndent + 2
+	var added []string
+	if item.annotationsLine < 0 {
+		added = append(added, strings.Repeat(" ", item.fieldIndent)+"annotations:")
+	} else {
+		line := lines[item.annotationsLine]
+		idx := strings.Index(line, "annotations:") + len("annotations:")
+		switch yamlValue(line[idx:]) {
+		case "":
+		case "{}":
+			lines[item.annotationsLine] = line[:idx]
+		default:
+			return nil, errors.Errorf("cannot modify the inline annotations of the %s trigger %s", section, name)
+		}
+
+		insertAt = item.annotationsLine + 1
+		first := true
+		for i := item.annotationsLine + 1; i < item.end; i++ {
+			line := lines[i]
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if indent <= item.fieldIndent {
+				break
+			}
+			if first {
+				childIndent = indent
+				first = false
+			}
+			insertAt = i + 1
+			idx := strings.Index(trimmed, ":")
+			if indent != childIndent || idx < 0 {
+				continue
+			}
+			key := strings.Trim(trimmed[:idx], "\"'")
+			if v, ok := remaining[key]; ok {
+				lines[i] = line[:indent] + trimmed[:idx] + ": " + strconv.Quote(v)
+				delete(remaining, key)
+			}
+		}
+	}
+
+	var keys []string
+	for k := range remaining {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		added = append(added, strings.Repeat(" ", childIndent)+k+": "+strconv.Quote(remaining[k]))
+	}
+	if len(keys) == 0 {
+		return lines, nil
+	}
+
+	answer := append([]string{}, lines[:insertAt]...)
+	answer = append(answer, added...)
+	return append(answer, lines[insertAt:]...), nil
+}
+
+// yamlValue returns the scalar value of the given YAML text without any quotes or trailing comment
+func yamlValue(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) > 1 && (text[0] == '"' || text[0] == '\'') {
+		end := strings.IndexByte(text[1:], text[0])
+		if end >= 0 {
+			return text[1 : end+1]
+		}
+	}
+	if idx := strings.Index(text, " #"); idx >= 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,77 @@
+package owners_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/owners"
+	pipelineowners "github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/lighthouse-client/pkg/config/job"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineOwners(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	err = files.CopyDir("test_data", tmpDir, true)
+	require.NoError(t, err, "failed to copy test_data to %s", tmpDir)
+
+	_, o := owners.NewCmdPipelineOwners()
+
+	o.Dir = tmpDir
+	o.TeamChannels = []string{"@myorg/release=#releases"}
+	o.Channel = "#builds"
+	o.Ctx = context.TODO()
+	err = o.Run()
+	require.NoError(t, err, "failed to run owners")
+
+	path := filepath.Join(tmpDir, ".lighthouse", "jenkins-x", "triggers.yaml")
+	require.Equal(t, []string{path}, o.ModifiedFiles, "modified files")
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err, "failed to read %s", path)
+	text := string(data)
+	assert.Contains(t, text, "  # verifies pull requests\n  presubmits:\n", "should have preserved the comment on the presubmits")
+	assert.Contains(t, text, "  - name: release # releases the main branch\n", "should have preserved the comment on the postsubmit")
+	assert.Contains(t, text, "    cron: \"10 1 * * *\"\n", "should have preserved the formatting of the periodic")
+
+	cfg := &triggerconfig.Config{}
+	err = yamls.LoadFile(path, cfg)
+	require.NoError(t, err, "failed to load %s", path)
+
+	require.Len(t, cfg.Spec.Presubmits, 1, "presubmits")
+	require.Len(t, cfg.Spec.Postsubmits, 1, "postsubmits")
+
+	pr := pipelineowners.FromAnnotations(cfg.Spec.Presubmits[0].Annotations)
+	require.NotNil(t, pr, "owner of presubmit")
+	assert.Equal(t, "@myorg/platform", pr.Team, "team of presubmit")
+	assert.Equal(t, "#builds", pr.Channel, "channel of presubmit")
+
+	release := pipelineowners.FromAnnotations(cfg.Spec.Postsubmits[0].Annotations)
+	require.NotNil(t, release, "owner of postsubmit")
+	assert.Equal(t, "@myorg/release", release.Team, "team of postsubmit")
+	assert.Equal(t, "#releases", release.Channel, "channel of postsubmit")
+	assert.Equal(t, "releases the repository", cfg.Spec.Postsubmits[0].Annotations["description"], "should have kept the other annotations of postsubmit")
+
+	periodics := &struct {
+		Spec struct {
+			Periodics []job.Periodic `json:"periodics,omitempty"`
+		} `json:"spec"`
+	}{}
+	err = yamls.LoadFile(path, periodics)
+	require.NoError(t, err, "failed to load %s", path)
+	require.Len(t, periodics.Spec.Periodics, 1, "periodics")
+
+	nightly := pipelineowners.FromAnnotations(periodics.Spec.Periodics[0].Annotations)
+	require.NotNil(t, nightly, "owner of periodic")
+	assert.Equal(t, "@myorg/ops", nightly.Team, "team of periodic")
+	assert.Equal(t, "#builds", nightly.Channel, "channel of periodic")
+	assert.Equal(t, "10 1 * * *", periodics.Spec.Periodics[0].Cron, "cron of periodic")
+}
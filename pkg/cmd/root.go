@@ -2,18 +2,27 @@ package cmd
 
 import (
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/activities"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/admission"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/convert"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/doctor"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/effective"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/env"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/eval"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/exportconfig"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/fmt"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/get"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/getlog"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/grid"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/importconfig"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/lint"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/logrelay"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/override"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/owners"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/pod"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/schedules"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/set"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/skipstage"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/start"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/stop"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/version"
@@ -38,18 +47,27 @@ func Main() *cobra.Command {
 	}
 
 	cmd.AddCommand(cobras.SplitCommand(activities.NewCmdActivities()))
+	cmd.AddCommand(cobras.SplitCommand(admission.NewCmdPipelineAdmission()))
 	cmd.AddCommand(cobras.SplitCommand(convert.NewCmdPipelineConvert()))
+	cmd.AddCommand(cobras.SplitCommand(doctor.NewCmdPipelineDoctor()))
 	cmd.AddCommand(cobras.SplitCommand(effective.NewCmdPipelineEffective()))
 	cmd.AddCommand(cobras.SplitCommand(env.NewCmdPipelineEnv()))
+	cmd.AddCommand(cobras.SplitCommand(eval.NewCmdPipelineEval()))
+	cmd.AddCommand(cobras.SplitCommand(exportconfig.NewCmdPipelineExportConfig()))
 	cmd.AddCommand(cobras.SplitCommand(get.NewCmdPipelineGet()))
 	cmd.AddCommand(cobras.SplitCommand(getlog.NewCmdGetBuildLogs()))
 	cmd.AddCommand(cobras.SplitCommand(grid.NewCmdPipelineGrid()))
 	cmd.AddCommand(cobras.SplitCommand(fmt.NewCmdPipelineFormat()))
 	cmd.AddCommand(cobras.SplitCommand(importcmd.NewCmdPipelineImport()))
+	cmd.AddCommand(cobras.SplitCommand(importconfig.NewCmdPipelineImportConfig()))
 	cmd.AddCommand(cobras.SplitCommand(lint.NewCmdPipelineLint()))
+	cmd.AddCommand(cobras.SplitCommand(logrelay.NewCmdPipelineLogRelay()))
 	cmd.AddCommand(cobras.SplitCommand(override.NewCmdPipelineOverride()))
+	cmd.AddCommand(cobras.SplitCommand(owners.NewCmdPipelineOwners()))
 	cmd.AddCommand(cobras.SplitCommand(pod.NewCmdGetBuildPods()))
+	cmd.AddCommand(cobras.SplitCommand(schedules.NewCmdPipelineSchedules()))
 	cmd.AddCommand(cobras.SplitCommand(set.NewCmdPipelineSet()))
+	cmd.AddCommand(cobras.SplitCommand(skipstage.NewCmdPipelineSkipStage()))
 	cmd.AddCommand(cobras.SplitCommand(start.NewCmdPipelineStart()))
 	cmd.AddCommand(cobras.SplitCommand(stop.NewCmdPipelineStop()))
 	cmd.AddCommand(cobras.SplitCommand(wait.NewCmdPipelineWait()))
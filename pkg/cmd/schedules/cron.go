@@ -0,0 +1,147 @@
+package schedules
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// cronDescriptors the predefined cron schedules and their equivalent minute and hour fields
+var cronDescriptors = map[string][]string{
+	"@yearly":   {"0", "0", "1", "1", "*"},
+	"@annually": {"0", "0", "1", "1", "*"},
+	"@monthly":  {"0", "0", "1", "*", "*"},
+	"@weekly":   {"0", "0", "*", "*", "0"},
+	"@daily":    {"0", "0", "*", "*", "*"},
+	"@midnight": {"0", "0", "*", "*", "*"},
+	"@hourly":   {"0", "*", "*", "*", "*"},
+}
+
+// cronFields returns the 5 standard cron fields for the given cron expression or nil if it cannot be parsed
+// such as for '@every' expressions
+func cronFields(cron string) []string {
+	cron = strings.TrimSpace(cron)
+	if fields, ok := cronDescriptors[cron]; ok {
+		answer := make([]string, len(fields))
+		copy(answer, fields)
+		return answer
+	}
+	fields := strings.Fields(cron)
+	switch len(fields) {
+	case 5:
+		return fields
+	case 6:
+		// lets ignore the optional seconds field
+		return fields[1:]
+	default:
+		return nil
+	}
+}
+
+// CronMinutes returns the minutes of the hour the given cron expression runs at or nil if it cannot be parsed
+func CronMinutes(cron string) []int {
+	fields := cronFields(cron)
+	if fields == nil {
+		return nil
+	}
+	return expandField(fields[0], 0, 59)
+}
+
+// expandField expands a cron field such as '*', '*/15', '5-10', '1,2,3' into its values
+func expandField(field string, min, max int) []int {
+	var answer []int
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		paths := strings.SplitN(part, "/", 2)
+		if len(paths) == 2 {
+			n, err := strconv.Atoi(paths[1])
+			if err != nil || n <= 0 {
+				return nil
+			}
+			step = n
+		}
+		from, to := min, max
+		r := paths[0]
+		if r != "*" {
+			bounds := strings.SplitN(r, "-", 2)
+			n, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil
+			}
+			from = n
+			to = n
+			if len(bounds) == 2 {
+				to, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil
+				}
+			} else if len(paths) == 2 {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil
+		}
+		for i := from; i <= to; i += step {
+			answer = append(answer, i)
+		}
+	}
+	return answer
+}
+
+// IsFixedMinute returns true if the cron expression runs at a single fixed minute of the hour so that it can be jittered
+func IsFixedMinute(cron string) bool {
+	fields := cronFields(cron)
+	if fields == nil {
+		return false
+	}
+	_, err := strconv.Atoi(fields[0])
+	return err == nil
+}
+
+// WithMinute returns the cron expression with the minute field replaced
+func WithMinute(cron string, minute int) string {
+	fields := strings.Fields(cron)
+	if len(fields) == 6 {
+		// lets preserve the optional seconds field
+		fields[1] = strconv.Itoa(minute)
+		return strings.Join(fields, " ")
+	}
+	fields = cronFields(cron)
+	if fields == nil {
+		return cron
+	}
+	fields[0] = strconv.Itoa(minute)
+	return strings.Join(fields, " ")
+}
+
+// JitterMinute returns a stable minute for the given name which avoids any minutes which are already at capacity
+func JitterMinute(name string, counts map[int]int, maxPerMinute int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	start := int(h.Sum32() % 60)
+	for i := 0; i < 60; i++ {
+		m := (start + i) % 60
+		// lets avoid the top of the hour
+		if m == 0 {
+			continue
+		}
+		if counts[m] < maxPerMinute {
+			return m
+		}
+	}
+	return start
+}
+
+// formatMinutes formats the minutes for display
+func formatMinutes(minutes []int) string {
+	if len(minutes) == 0 {
+		return ""
+	}
+	var values []string
+	for _, m := range minutes {
+		values = append(values, fmt.Sprintf(":%02d", m))
+	}
+	return strings.Join(values, " ")
+}
@@ -0,0 +1,403 @@
+package schedules
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/constants"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/triggers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/outputformat"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/table"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/config/job"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Dir                 string
+	Format              string
+	Namespace           string
+	LighthouseConfigMap string
+	MaxPerMinute        int
+	Apply               bool
+	KubeClient          kubernetes.Interface
+	Out                 io.Writer
+	Results             []*Schedule
+}
+
+// Schedule a periodic pipeline and its cron schedule
+type Schedule struct {
+	Name      string `json:"name"`
+	Source    string `json:"source"`
+	Cron      string `json:"cron"`
+	Minutes   []int  `json:"minutes,omitempty"`
+	Clustered bool   `json:"clustered,omitempty"`
+	Suggested string `json:"suggested,omitempty"`
+}
+
+// periodicTriggers the periodics in a local triggers.yaml file
+type periodicTriggers struct {
+	Spec struct {
+		Periodics []job.Periodic `json:"periodics,omitempty"`
+	} `json:"spec"`
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Displays the cron schedules of the periodic pipelines highlighting any minutes of the hour with too many pipelines starting at once.
+
+		By default the periodics in the lighthouse configuration in the cluster are used. If a directory is specified then all the triggers.yaml files in it are used such as for a pipeline catalog.
+`)
+
+	cmdExample = templates.Examples(`
+		# View the cron schedules of the periodic pipelines in the cluster
+		jx pipeline schedules
+
+		# View the cron schedules of the periodic pipelines in a pipeline catalog
+		jx pipeline schedules --dir .
+
+		# Apply jitter to the cron schedules of any clustered periodic pipelines in a pipeline catalog
+		jx pipeline schedules --dir . --apply
+	`)
+)
+
+// NewCmdPipelineSchedules creates the command
+func NewCmdPipelineSchedules() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "schedules",
+		Short:   "Displays the cron schedules of the periodic pipelines highlighting clustered schedules",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"schedule", "cron", "periodics"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", "", "The directory to recursively look for triggers.yaml files. If not specified the lighthouse configuration in the cluster is used")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "The output format such as 'yaml' or 'json'")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The kubernetes namespace to use. If not specified the default namespace is used")
+	cmd.Flags().StringVarP(&o.LighthouseConfigMap, "configmap", "", constants.LighthouseConfigMapName, "The name of the Lighthouse ConfigMap to find the trigger configurations")
+	cmd.Flags().IntVarP(&o.MaxPerMinute, "max-per-minute", "", 2, "The maximum number of periodic pipelines which should start at the same minute of the hour")
+	cmd.Flags().BoolVarP(&o.Apply, "apply", "", false, "Applies the suggested jittered schedules to the triggers.yaml files. Requires the --dir option")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	if o.Dir == "" {
+		if o.Apply {
+			return options.MissingOption("dir")
+		}
+		var err error
+		o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create kube client")
+		}
+	}
+	if o.MaxPerMinute <= 0 {
+		o.MaxPerMinute = 1
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	if o.Dir != "" {
+		o.Results, err = o.loadDirSchedules(o.Dir)
+	} else {
+		o.Results, err = o.loadClusterSchedules()
+	}
+	if err != nil {
+		return err
+	}
+
+	AnalyseSchedules(o.Results, o.MaxPerMinute)
+
+	if o.Apply {
+		err = o.applySchedules()
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply schedules")
+		}
+	}
+
+	if o.Format != "" {
+		return outputformat.Marshal(o.Results, o.Out, o.Format)
+	}
+
+	t := table.CreateTable(o.Out)
+	t.AddRow("NAME", "SOURCE", "CRON", "MINUTES", "SUGGESTED")
+	for _, s := range o.Results {
+		minutes := formatMinutes(s.Minutes)
+		if s.Clustered {
+			minutes = termcolor.ColorWarning(minutes)
+		}
+		t.AddRow(s.Name, s.Source, s.Cron, minutes, s.Suggested)
+	}
+	t.Render()
+
+	clustered := 0
+	for _, s := range o.Results {
+		if s.Clustered {
+			clustered++
+		}
+	}
+	if clustered > 0 {
+		log.Logger().Warnf("%d of %d periodic pipelines start at a clustered minute of the hour", clustered, len(o.Results))
+		if !o.Apply && o.Dir != "" {
+			log.Logger().Infof("you can apply the suggested schedules via: %s", info("jx pipeline schedules --dir "+o.Dir+" --apply"))
+		}
+	}
+	return nil
+}
+
+func (o *Options) loadClusterSchedules() ([]*Schedule, error) {
+	ctx := o.GetContext()
+	cfg, err := triggers.LoadLighthouseConfig(ctx, o.KubeClient, o.Namespace, o.LighthouseConfigMap, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load lighthouse config")
+	}
+	var answer []*Schedule
+	for i := range cfg.Periodics {
+		p := &cfg.Periodics[i]
+		answer = append(answer, &Schedule{
+			Name:   p.Name,
+			Source: "ConfigMap/" + o.LighthouseConfigMap,
+			Cron:   p.Cron,
+		})
+	}
+	return answer, nil
+}
+
+func (o *Options) loadDirSchedules(dir string) ([]*Schedule, error) {
+	var answer []*Schedule
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info == nil || info.IsDir() || info.Name() != "triggers.yaml" {
+			return nil
+		}
+		pt := &periodicTriggers{}
+		err = yamls.LoadFile(path, pt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s", path)
+		}
+		for i := range pt.Spec.Periodics {
+			p := &pt.Spec.Periodics[i]
+			answer = append(answer, &Schedule{
+				Name:   p.Name,
+				Source: path,
+				Cron:   p.Cron,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find triggers in dir %s", dir)
+	}
+	return answer, nil
+}
+
+// AnalyseSchedules calculates the minutes of the hour each schedule starts at, marks the ones which
+// start at the top of the hour or at a minute with more than the maximum number of pipelines and
+// suggests a jittered schedule for them
+func AnalyseSchedules(schedules []*Schedule, maxPerMinute int) {
+	counts := map[int]int{}
+	for _, s := range schedules {
+		s.Minutes = CronMinutes(s.Cron)
+		for _, m := range s.Minutes {
+			counts[m]++
+		}
+	}
+
+	// lets find the clustered schedules before any suggestions modify the counts
+	for _, s := range schedules {
+		for _, m := range s.Minutes {
+			if m == 0 || counts[m] > maxPerMinute {
+				s.Clustered = true
+				break
+			}
+		}
+	}
+
+	// lets use a stable order so the suggestions are repeatable
+	sorted := append([]*Schedule{}, schedules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Source+"/"+sorted[i].Name < sorted[j].Source+"/"+sorted[j].Name
+	})
+	for _, s := range sorted {
+		if !s.Clustered || !IsFixedMinute(s.Cron) {
+			continue
+		}
+		old := s.Minutes[0]
+		if old != 0 && counts[old] <= maxPerMinute {
+			// earlier suggestions have already moved enough of the schedules away from this minute
+			continue
+		}
+		counts[old]--
+		minute := JitterMinute(s.Source+"/"+s.Name, counts, maxPerMinute)
+		counts[minute]++
+		s.Suggested = WithMinute(s.Cron, minute)
+	}
+}
+
+// applySchedules modifies the cron expressions in the triggers.yaml files to the suggested values
+func (o *Options) applySchedules() error {
+	var paths []string
+	m := map[string][]*Schedule{}
+	for _, s := range o.Results {
+		if s.Suggested == "" {
+			continue
+		}
+		if m[s.Source] == nil {
+			paths = append(paths, s.Source)
+		}
+		m[s.Source] = append(m[s.Source], s)
+	}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load file %s", path)
+		}
+		lines := strings.Split(string(data), "\n")
+		err = replaceCrons(lines, m[path])
+		if err != nil {
+			return errors.Wrapf(err, "failed to modify file %s", path)
+		}
+		err = ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", path)
+		}
+		log.Logger().Infof("modified file %s", info(path))
+	}
+	return nil
+}
+
+// replaceCrons replaces the cron expression of each periodic with its suggestion. The cron line is found via the
+// name of the periodic and its own cron field so that a cron which contains another one is never modified by mistake
+func replaceCrons(lines []string, schedules []*Schedule) error {
+	names := map[string]*Schedule{}
+	for _, s := range schedules {
+		names[s.Name] = s
+	}
+	modified := map[string]bool{}
+
+	itemIndent := -1
+	fieldIndent := -1
+	name := ""
+	cronLine := -1
+	apply := func() {
+		s := names[name]
+		if s == nil || cronLine < 0 || modified[name] {
+			return
+		}
+		line := lines[cronLine]
+		idx := strings.Index(line, "cron:") + len("cron:")
+		if yamlValue(line[idx:]) == s.Cron {
+			lines[cronLine] = line[:idx] + strings.Replace(line[idx:], s.Cron, s.Suggested, 1)
+			modified[name] = true
+		}
+	}
+
+	inPeriodics := false
+	periodicsIndent := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(trimmed, "periodics:") {
+			inPeriodics = true
+			periodicsIndent = indent
+			itemIndent = -1
+			continue
+		}
+		if !inPeriodics {
+			continue
+		}
+		if indent <= periodicsIndent && !strings.HasPrefix(trimmed, "- ") {
+			apply()
+			inPeriodics = false
+			name = ""
+			cronLine = -1
+			continue
+		}
+
+		field := ""
+		if strings.HasPrefix(trimmed, "- ") && (itemIndent < 0 || indent == itemIndent) {
+			// the start of the next periodic
+			apply()
+			itemIndent = indent
+			fieldIndent = indent + 2 + len(trimmed[2:]) - len(strings.TrimLeft(trimmed[2:], " "))
+			name = ""
+			cronLine = -1
+			field = strings.TrimLeft(trimmed[2:], " ")
+		} else if indent == fieldIndent {
+			field = trimmed
+		}
+		switch {
+		case strings.HasPrefix(field, "name:"):
+			name = yamlValue(strings.TrimPrefix(field, "name:"))
+		case strings.HasPrefix(field, "cron:"):
+			cronLine = i
+		}
+	}
+	if inPeriodics {
+		apply()
+	}
+
+	for _, s := range schedules {
+		if !modified[s.Name] {
+			return errors.Errorf("could not find cron %s for periodic %s", s.Cron, s.Name)
+		}
+	}
+	return nil
+}
+
+// yamlValue returns the scalar value of a YAML field without any quotes or comment
+func yamlValue(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) > 1 && (text[0] == '"' || text[0] == '\'') {
+		end := strings.IndexByte(text[1:], text[0])
+		if end >= 0 {
+			return text[1 : end+1]
+		}
+	}
+	if idx := strings.Index(text, " #"); idx >= 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,90 @@
+package schedules_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/schedules"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedules(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	err = files.CopyDir("test_data", tmpDir, true)
+	require.NoError(t, err, "failed to copy test_data to %s", tmpDir)
+
+	_, o := schedules.NewCmdPipelineSchedules()
+
+	buf := &bytes.Buffer{}
+	o.Dir = tmpDir
+	o.Apply = true
+	o.Out = buf
+	o.Ctx = context.TODO()
+	err = o.Run()
+	require.NoError(t, err, "failed to run schedules")
+
+	t.Logf("results:\n%s\n", buf.String())
+
+	require.Len(t, o.Results, 7, "results")
+	m := map[string]*schedules.Schedule{}
+	for _, s := range o.Results {
+		m[s.Name] = s
+	}
+
+	for _, name := range []string{"nightly", "cleanup", "report", "metrics", "poll", "rotate"} {
+		require.NotNil(t, m[name], "no schedule for %s", name)
+		assert.True(t, m[name].Clustered, "schedule %s should be clustered", name)
+	}
+	require.NotNil(t, m["backup"], "no schedule for backup")
+	assert.False(t, m["backup"].Clustered, "schedule backup should not be clustered")
+	assert.Empty(t, m["backup"].Suggested, "schedule backup should not have a suggestion")
+	assert.Empty(t, m["poll"].Suggested, "schedule poll should not have a suggestion as it does not start at a fixed minute")
+
+	// cleanup, report and metrics all start at :30 so moving cleanup is enough to get under the limit
+	for _, name := range []string{"report", "metrics"} {
+		assert.Empty(t, m[name].Suggested, "schedule %s should not have a suggestion once cleanup has moved", name)
+	}
+
+	for _, name := range []string{"nightly", "cleanup", "rotate"} {
+		s := m[name]
+		require.NotEmpty(t, s.Suggested, "schedule %s should have a suggestion", name)
+		minutes := schedules.CronMinutes(s.Suggested)
+		require.Len(t, minutes, 1, "minutes for suggestion %s of schedule %s", s.Suggested, name)
+		assert.NotEqual(t, 0, minutes[0], "suggested minute for schedule %s", name)
+		assert.NotEqual(t, 30, minutes[0], "suggested minute for schedule %s", name)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(tmpDir, ".lighthouse", "nightly", "triggers.yaml"))
+	require.NoError(t, err, "failed to load modified triggers")
+	text := string(data)
+	assert.Contains(t, text, `cron: "`+m["nightly"].Suggested+`"`, "modified triggers should contain the nightly suggestion")
+	assert.Contains(t, text, `cron: "`+m["cleanup"].Suggested+`"`, "modified triggers should contain the cleanup suggestion")
+
+	// the cron of backup contains the cron of rotate so lets make sure only rotate is modified
+	data, err = ioutil.ReadFile(filepath.Join(tmpDir, ".lighthouse", "backup", "triggers.yaml"))
+	require.NoError(t, err, "failed to load modified triggers")
+	text = string(data)
+	assert.Contains(t, text, `cron: "10 0 * * *"`, "modified triggers should not modify the backup cron")
+	assert.Contains(t, text, `cron: "`+m["rotate"].Suggested+`"`, "modified triggers should contain the rotate suggestion")
+}
+
+func TestCronMinutes(t *testing.T) {
+	testCases := map[string][]int{
+		"0 * * * *":     {0},
+		"*/15 * * * *":  {0, 15, 30, 45},
+		"5-9/2 * * * *": {5, 7, 9},
+		"3,7 1 * * *":   {3, 7},
+		"@daily":        {0},
+		"@every 1h":     nil,
+	}
+	for cron, expected := range testCases {
+		assert.Equal(t, expected, schedules.CronMinutes(cron), "minutes for cron %s", cron)
+	}
+}
@@ -0,0 +1,170 @@
+package skipstage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-kube-client/v3/pkg/kubeclient"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelPipelineRun the label tekton adds to pods with the name of the PipelineRun
+	LabelPipelineRun = "tekton.dev/pipelineRun"
+
+	// LabelPipelineTask the label tekton adds to pods with the name of the pipeline task
+	LabelPipelineTask = "tekton.dev/pipelineTask"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+
+	Stages       []string
+	Reason       string
+	PipelineRun  string
+	Pod          string
+	Namespace    string
+	KubeClient   kubernetes.Interface
+	TektonClient tektonclient.Interface
+}
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Marks one or more stages of the current pipeline as intentionally skipped.
+
+		This is intended to be run inside a pipeline step, for example when a change only touches documentation, so that 'jx pipeline get', 'jx pipeline grid' and 'jx pipeline activities' display the stages as skipped rather than missing or failed.
+
+		The PipelineRun and stage default to the labels on the current pod.
+`)
+
+	cmdExample = templates.Examples(`
+		# Marks the current stage as skipped
+		jx pipeline skip-stage --reason "docs only change"
+
+		# Marks other stages of the current pipeline as skipped
+		jx pipeline skip-stage --stage build --stage promote --reason "docs only change"
+	`)
+)
+
+// NewCmdPipelineSkipStage creates the command
+func NewCmdPipelineSkipStage() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "skip-stage",
+		Short:   "Marks stages of the current pipeline as intentionally skipped",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Aliases: []string{"skip"},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&o.Stages, "stage", "s", nil, "The names of the pipeline tasks to skip. Defaults to the pipeline task of the current pod")
+	cmd.Flags().StringVarP(&o.Reason, "reason", "r", "", "The reason the stages are skipped such as 'docs only change'")
+	cmd.Flags().StringVarP(&o.PipelineRun, "pipeline-run", "", "", "The name of the PipelineRun. Defaults to the PipelineRun of the current pod")
+	cmd.Flags().StringVarP(&o.Pod, "pod", "", os.Getenv("HOSTNAME"), "The name of the current pod used to default the PipelineRun and stage")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The kubernetes namespace of the PipelineRun. If not specified the default namespace is used")
+
+	o.BaseOptions.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies things are setup correctly
+func (o *Options) Validate() error {
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create kube client")
+	}
+	if o.TektonClient == nil {
+		f := kubeclient.NewFactory()
+		cfg, err := f.CreateKubeConfig()
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubernetes config")
+		}
+		o.TektonClient, err = tektonclient.NewForConfig(cfg)
+		if err != nil {
+			return errors.Wrap(err, "error building tekton client")
+		}
+	}
+
+	if o.PipelineRun == "" || len(o.Stages) == 0 {
+		if o.Pod == "" {
+			return options.MissingOption("pipeline-run")
+		}
+		ctx := o.GetContext()
+		pod, err := o.KubeClient.CoreV1().Pods(o.Namespace).Get(ctx, o.Pod, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find pod %s in namespace %s", o.Pod, o.Namespace)
+		}
+		if o.PipelineRun == "" {
+			o.PipelineRun = pod.Labels[LabelPipelineRun]
+			if o.PipelineRun == "" {
+				return errors.Errorf("pod %s has no label %s so please specify --pipeline-run", o.Pod, LabelPipelineRun)
+			}
+		}
+		if len(o.Stages) == 0 {
+			stage := pod.Labels[LabelPipelineTask]
+			if stage == "" {
+				return errors.Errorf("pod %s has no label %s so please specify --stage", o.Pod, LabelPipelineTask)
+			}
+			o.Stages = []string{stage}
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate options")
+	}
+
+	ctx := o.GetContext()
+	return o.skipStages(ctx)
+}
+
+// skipStages patches the annotations rather than updating the whole PipelineRun as it is being
+// reconciled by the tekton controller which would often cause conflicts
+func (o *Options) skipStages(ctx context.Context) error {
+	annotations := map[string]string{}
+	for _, stage := range o.Stages {
+		annotations[pipelines.SkipStageAnnotation(stage)] = o.Reason
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the annotations patch")
+	}
+	_, err = o.TektonClient.TektonV1beta1().PipelineRuns(o.Namespace).Patch(ctx, o.PipelineRun, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch PipelineRun %s in namespace %s", o.PipelineRun, o.Namespace)
+	}
+	for _, stage := range o.Stages {
+		log.Logger().Infof("marked stage %s of PipelineRun %s as skipped", info(stage), info(o.PipelineRun))
+	}
+	return nil
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/jenkins-x/lighthouse-client/pkg/filebrowser"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/constants"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/hooks"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/sourcerepos"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/triggers"
@@ -57,6 +60,8 @@ import (
 type Options struct {
 	options.BaseOptions
 	lighthouses.ResolverOptions
+	Hooks  hooks.Options
+	DryRun dryrun.Options
 
 	Args                []string
 	Output              string
@@ -116,6 +121,9 @@ var (
 
 		# Start the given local pipeline file
 		jx pipeline start -F .lighthouse/jenkins-x/mypipeline.yaml
+
+		# Display the LighthouseJob which would be created without starting the pipeline
+		jx pipeline start --dry-run --diff foo
 	`)
 )
 
@@ -152,12 +160,17 @@ func NewCmdPipelineStart() (*cobra.Command, *Options) {
 	cmd.Flags().DurationVarP(&o.WaitDuration, "duration", "", time.Minute*20, "Maximum duration to wait for one or more matching triggers to be setup in Lighthouse. Useful for when a new repository is being imported via GitOps")
 	cmd.Flags().DurationVarP(&o.PollPeriod, "poll-period", "", time.Second*2, "Poll period when waiting for one or more matching triggers to be setup in Lighthouse. Useful for when a new repository is being imported via GitOps")
 
+	o.Hooks.AddFlags(cmd)
+	o.DryRun.AddFlags(cmd)
 	return cmd, o
 }
 
 // Validate verifies things are setup correctly
 func (o *Options) Validate() error {
-	var err error
+	err := o.DryRun.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "invalid dry run options")
+	}
 	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create kube client")
@@ -276,7 +289,6 @@ func (o *Options) processFile(path string) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to load %s", path)
 	}
-	ns := o.Namespace
 	if o.Context == "" {
 		o.Context = "trigger"
 	}
@@ -343,17 +355,14 @@ func (o *Options) processFile(path string) error {
 		},
 	}
 
-	lhjob.Labels, lhjob.Annotations = jobutil.LabelsAndAnnotationsForSpec(lhjob.Spec, nil, nil)
+	lhjob.Labels, lhjob.Annotations = jobutil.LabelsAndAnnotationsForSpec(lhjob.Spec, nil, owners.Annotations(pr.Annotations))
 	lhjob.GenerateName = naming.ToValidName(owner+"-"+repo) + "-"
 
-	launchClient := launcher.NewLauncher(o.LHClient, o.Namespace)
-	lhjob, err = launchClient.Launch(lhjob)
+	err = o.runPreStartHook(lhjob)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create lighthousejob %s in namespace %s", lhjob.Name, ns)
+		return err
 	}
-
-	log.Logger().Infof("created lighthousejob %s in namespace %s", info(lhjob.Name), info(ns))
-	return nil
+	return o.launchJob(lhjob)
 }
 
 func (o *Options) createLighthouseJob(jobName string, cfg *config.Config) error {
@@ -488,16 +497,59 @@ func (o *Options) createLighthouseJob(jobName string, cfg *config.Config) error
 		},
 	}
 
-	lhjob.Labels, lhjob.Annotations = jobutil.LabelsAndAnnotationsForSpec(lhjob.Spec, nil, nil)
+	lhjob.Labels, lhjob.Annotations = jobutil.LabelsAndAnnotationsForSpec(lhjob.Spec, nil, owners.Annotations(base.Annotations))
 	lhjob.GenerateName = naming.ToValidName(owner+"-"+repo) + "-"
 
-	launchClient := launcher.NewLauncher(o.LHClient, o.Namespace)
-	lhjob, err = launchClient.Launch(lhjob)
+	err = o.runPreStartHook(lhjob)
+	if err != nil {
+		return err
+	}
+	return o.launchJob(lhjob)
+}
+
+// launchJob creates the LighthouseJob unless this is a dry run
+func (o *Options) launchJob(lhjob *v1alpha1.LighthouseJob) error {
+	ns := o.Namespace
+	switch {
+	case o.DryRun.IsClient():
+		return o.DryRun.Report(dryrun.Create, "LighthouseJob", lhjob.GenerateName, ns, nil, lhjob)
+	case o.DryRun.IsServer():
+		created, err := o.LHClient.LighthouseV1alpha1().LighthouseJobs(ns).Create(o.GetContext(), lhjob, o.DryRun.CreateOptions())
+		if err != nil {
+			return errors.Wrapf(err, "failed to create lighthousejob %s in namespace %s", lhjob.GenerateName, ns)
+		}
+		return o.DryRun.Report(dryrun.Create, "LighthouseJob", created.Name, ns, nil, created)
+	}
+
+	launchClient := launcher.NewLauncher(o.LHClient, ns)
+	lhjob, err := launchClient.Launch(lhjob)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create lighthousejob %s in namespace %s", lhjob.Name, ns)
 	}
 
 	log.Logger().Infof("created lighthousejob %s in namespace %s", info(lhjob.Name), info(ns))
+	return o.DryRun.PrintDiff("LighthouseJob", lhjob.Name, nil, lhjob)
+}
+
+// runPreStartHook invokes the pre-start hook so that policies can veto the LighthouseJob being created
+func (o *Options) runPreStartHook(lhjob *v1alpha1.LighthouseJob) error {
+	spec := &lhjob.Spec
+	event := &hooks.Event{
+		Hook:          hooks.PreStart,
+		Namespace:     o.Namespace,
+		Context:       spec.Context,
+		Path:          o.File,
+		LighthouseJob: lhjob,
+	}
+	if spec.Refs != nil {
+		event.Owner = spec.Refs.Org
+		event.Repository = spec.Refs.Repo
+		event.Branch = spec.Refs.BaseRef
+	}
+	err := o.Hooks.Run(event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run hooks before starting pipeline %s", spec.Job)
+	}
 	return nil
 }
 
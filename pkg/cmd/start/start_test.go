@@ -1,18 +1,23 @@
 package start_test
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/start"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
 	"github.com/jenkins-x/go-scm/scm"
 	fakescm "github.com/jenkins-x/go-scm/scm/driver/fake"
 	jenkinsio "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io"
 	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	lhapi "github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	lhclient "github.com/jenkins-x/lighthouse-client/pkg/client/clientset/versioned"
 	fakelh "github.com/jenkins-x/lighthouse-client/pkg/client/clientset/versioned/fake"
+	lhv1alpha1 "github.com/jenkins-x/lighthouse-client/pkg/client/clientset/versioned/typed/lighthouse/v1alpha1"
 	"github.com/jenkins-x/lighthouse-client/pkg/config"
 	"github.com/jenkins-x/lighthouse-client/pkg/config/job"
 	"github.com/stretchr/testify/assert"
@@ -297,3 +302,118 @@ func createGitHubSourceRepository(ns, org, repo string) *jenkinsv1.SourceReposit
 		},
 	}
 }
+
+func TestPipelineStartDryRun(t *testing.T) {
+	ns := "jx"
+	owner := "myorg"
+	repo := "myrepo"
+	branch := "master"
+	fullName := scm.Join(owner, repo)
+
+	scmClient, fakeScm := fakescm.NewDefault()
+	fakeScm.Commits[branch] = &scm.Commit{
+		Sha:     "1234",
+		Message: "fix: my commit",
+	}
+
+	cfg := &config.Config{
+		JobConfig: config.JobConfig{
+			Postsubmits: map[string][]job.Postsubmit{
+				fullName: {
+					{
+						Base: job.Base{
+							Name:  "release",
+							Agent: job.TektonPipelineAgent,
+							PipelineRunSpec: &v1beta1.PipelineRunSpec{
+								PipelineRef: &v1beta1.PipelineRef{
+									Name:       "my-pipeline",
+									APIVersion: "v1beta1",
+								},
+							},
+						},
+						Reporter: job.Reporter{
+							Context: "release",
+						},
+					},
+				},
+			},
+		},
+	}
+	configData, err := yaml.Marshal(cfg)
+	require.NoError(t, err, "failed to marshal lighthouse config %v to YAML", cfg)
+
+	for _, dryRun := range []string{dryrun.Client, dryrun.Server} {
+		_, o := start.NewCmdPipelineStart()
+		o.DryRun.DryRun = dryRun
+		o.DryRun.Out = &bytes.Buffer{}
+		o.ScmClients = map[string]*scm.Client{
+			fakeGitServer: scmClient,
+		}
+		o.KubeClient = fake.NewSimpleClientset(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      o.LighthouseConfigMap,
+					Namespace: ns,
+				},
+				Data: map[string]string{
+					"config.yaml": string(configData),
+				},
+			},
+		)
+		lhClient := &recordingLHClient{Interface: fakelh.NewSimpleClientset()}
+		o.LHClient = lhClient
+		o.JXClient = fakejx.NewSimpleClientset(
+			createGitHubSourceRepository(ns, owner, repo),
+		)
+		o.Namespace = ns
+		o.GitUsername = "myuser"
+		o.GitToken = "mytoken"
+		o.Branch = branch
+		o.Ctx = context.Background()
+		o.Args = []string{fullName + "/" + branch}
+
+		err = o.Run()
+		require.NoError(t, err, "failed to run command for dry run %s", dryRun)
+
+		if dryRun == dryrun.Client {
+			assert.Empty(t, lhClient.creates, "should not have sent any requests to create a LighthouseJob for dry run %s", dryRun)
+
+			ctx := context.Background()
+			lhResources, err := lhClient.Interface.LighthouseV1alpha1().LighthouseJobs(ns).List(ctx, metav1.ListOptions{})
+			require.NoError(t, err, "should not fail to list lhjobs in namespace %s", ns)
+			assert.Empty(t, lhResources.Items, "should not have created a lhjob for dry run %s", dryRun)
+			continue
+		}
+		require.Len(t, lhClient.creates, 1, "should have sent a request to create a LighthouseJob for dry run %s", dryRun)
+		assert.Equal(t, []string{metav1.DryRunAll}, lhClient.creates[0].DryRun, "create options for dry run %s", dryRun)
+	}
+}
+
+// recordingLHClient records the options used to create LighthouseJobs as the fake clients ignore them
+type recordingLHClient struct {
+	lhclient.Interface
+	creates []metav1.CreateOptions
+}
+
+func (c *recordingLHClient) LighthouseV1alpha1() lhv1alpha1.LighthouseV1alpha1Interface {
+	return &recordingLHV1alpha1{LighthouseV1alpha1Interface: c.Interface.LighthouseV1alpha1(), client: c}
+}
+
+type recordingLHV1alpha1 struct {
+	lhv1alpha1.LighthouseV1alpha1Interface
+	client *recordingLHClient
+}
+
+func (c *recordingLHV1alpha1) LighthouseJobs(ns string) lhv1alpha1.LighthouseJobInterface {
+	return &recordingLighthouseJobs{LighthouseJobInterface: c.LighthouseV1alpha1Interface.LighthouseJobs(ns), client: c.client}
+}
+
+type recordingLighthouseJobs struct {
+	lhv1alpha1.LighthouseJobInterface
+	client *recordingLHClient
+}
+
+func (c *recordingLighthouseJobs) Create(ctx context.Context, lhjob *lhapi.LighthouseJob, opts metav1.CreateOptions) (*lhapi.LighthouseJob, error) {
+	c.client.creates = append(c.client.creates, opts)
+	return c.LighthouseJobInterface.Create(ctx, lhjob, opts)
+}
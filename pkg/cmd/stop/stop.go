@@ -12,6 +12,7 @@ import (
 
 	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
 
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/pipelines"
@@ -41,6 +42,7 @@ import (
 // StopPipelineOptions contains the command line options
 type Options struct {
 	options.BaseOptions
+	DryRun dryrun.Options
 
 	Args         []string
 	Filter       string
@@ -73,6 +75,9 @@ var (
 
 		# Stop a pipeline for a specific context and branch
 		jx pipeline stop --context pr --branch PR-456
+
+		# Display the change to the PipelineRun which would be made without stopping it
+		jx pipeline stop --dry-run --diff myorg/myrepo/main
 	`)
 )
 
@@ -100,12 +105,16 @@ func NewCmdPipelineStop() (*cobra.Command, *Options) {
 	cmd.Flags().StringVarP(&o.Filter, "filter", "f", "",
 		"Filters all the available pipeline names")
 
+	o.DryRun.AddFlags(cmd)
 	return cmd, o
 }
 
 // Validate verifies things are setup correctly
 func (o *Options) Validate() error {
-	var err error
+	err := o.DryRun.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "invalid dry run options")
+	}
 	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create kube client")
@@ -263,23 +272,31 @@ func (o *Options) cancelPipelineRun() error {
 		log.Logger().Infof("no running pipelines available to stop")
 		return nil
 	}
-	var answer bool
-	if answer, err = o.Input.Confirm(fmt.Sprintf("cancel pipeline %s", name), true,
-		"you can always restart a cancelled pipeline with 'jx start pipeline'"); !answer {
-		return err
+	if !o.DryRun.IsDryRun() {
+		var answer bool
+		if answer, err = o.Input.Confirm(fmt.Sprintf("cancel pipeline %s", name), true,
+			"you can always restart a cancelled pipeline with 'jx start pipeline'"); !answer {
+			return err
+		}
 	}
-	args = []string{name}
 
 	pr := m[name]
 	if pr == nil {
 		return errors.Errorf("could not find PipelineRun %s", name)
 	}
 	prName := pr.Name
-	err = tektonlog.CancelPipelineRun(ctx, tektonClient, ns, pr)
+	if o.DryRun.IsClient() {
+		cancelled := pr.DeepCopy()
+		cancelled.Spec.Status = pipelineapi.PipelineRunSpecStatusCancelled
+		return o.DryRun.Report(dryrun.Update, "PipelineRun", prName, ns, pr, cancelled)
+	}
+	current, cancelled, err := tektonlog.CancelPipelineRunWithOptions(ctx, tektonClient, ns, pr, o.DryRun.UpdateOptions())
 	if err != nil {
 		return errors.Wrapf(err, "failed to cancel pipeline %s in namespace %s", prName, ns)
 	}
+	if o.DryRun.IsServer() {
+		return o.DryRun.Report(dryrun.Update, "PipelineRun", prName, ns, current, cancelled)
+	}
 	log.Logger().Infof("cancelled PipelineRun %s", termcolor.ColorInfo(prName))
-
-	return nil
+	return o.DryRun.PrintDiff("PipelineRun", prName, current, cancelled)
 }
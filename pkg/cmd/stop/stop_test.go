@@ -0,0 +1,114 @@
+package stop_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/cmd/stop"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	faketekton "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPipelineStopDryRun(t *testing.T) {
+	ns := "jx"
+	prName := "myorg-myrepo-master-1"
+
+	for _, dryRun := range []string{dryrun.Client, dryrun.Server} {
+		_, o := stop.NewCmdPipelineStop()
+		o.DryRun.DryRun = dryRun
+		o.DryRun.Out = &bytes.Buffer{}
+		o.Namespace = ns
+		o.Ctx = context.Background()
+		o.Input = &fakeInput{}
+		o.KubeClient = fake.NewSimpleClientset()
+		o.JXClient = fakejx.NewSimpleClientset()
+		tektonClient := &recordingTektonClient{
+			Interface: faketekton.NewSimpleClientset(
+				&v1beta1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      prName,
+						Namespace: ns,
+						Labels: map[string]string{
+							tektonlog.LabelOwner:   "myorg",
+							tektonlog.LabelRepo:    "myrepo",
+							tektonlog.LabelBranch:  "master",
+							tektonlog.LabelBuild:   "1",
+							tektonlog.LabelContext: "release",
+						},
+					},
+				},
+			),
+		}
+		o.TektonClient = tektonClient
+
+		err := o.Run()
+		require.NoError(t, err, "failed to run command for dry run %s", dryRun)
+
+		if dryRun == dryrun.Client {
+			assert.Empty(t, tektonClient.updates, "should not have sent any requests to update a PipelineRun for dry run %s", dryRun)
+
+			pr, err := tektonClient.Interface.TektonV1beta1().PipelineRuns(ns).Get(o.Ctx, prName, metav1.GetOptions{})
+			require.NoError(t, err, "failed to get PipelineRun %s", prName)
+			assert.Empty(t, pr.Spec.Status, "should not have cancelled the PipelineRun for dry run %s", dryRun)
+			continue
+		}
+		require.Len(t, tektonClient.updates, 1, "should have sent a request to update the PipelineRun for dry run %s", dryRun)
+		assert.Equal(t, []string{metav1.DryRunAll}, tektonClient.updates[0].DryRun, "update options for dry run %s", dryRun)
+	}
+}
+
+// fakeInput picks the first name and confirms everything
+type fakeInput struct {
+	input.Interface
+}
+
+func (f *fakeInput) PickNameWithDefault(names []string, message, defaultValue, help string) (string, error) {
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+func (f *fakeInput) Confirm(message string, defaultValue bool, help string) (bool, error) {
+	return true, nil
+}
+
+// recordingTektonClient records the options used to update PipelineRuns as the fake clients ignore them
+type recordingTektonClient struct {
+	tektonclient.Interface
+	updates []metav1.UpdateOptions
+}
+
+func (c *recordingTektonClient) TektonV1beta1() tektonv1beta1.TektonV1beta1Interface {
+	return &recordingTektonV1beta1{TektonV1beta1Interface: c.Interface.TektonV1beta1(), client: c}
+}
+
+type recordingTektonV1beta1 struct {
+	tektonv1beta1.TektonV1beta1Interface
+	client *recordingTektonClient
+}
+
+func (c *recordingTektonV1beta1) PipelineRuns(ns string) tektonv1beta1.PipelineRunInterface {
+	return &recordingPipelineRuns{PipelineRunInterface: c.TektonV1beta1Interface.PipelineRuns(ns), client: c.client}
+}
+
+type recordingPipelineRuns struct {
+	tektonv1beta1.PipelineRunInterface
+	client *recordingTektonClient
+}
+
+func (c *recordingPipelineRuns) Update(ctx context.Context, pr *v1beta1.PipelineRun, opts metav1.UpdateOptions) (*v1beta1.PipelineRun, error) {
+	c.client.updates = append(c.client.updates, opts)
+	return c.PipelineRunInterface.Update(ctx, pr, opts)
+}
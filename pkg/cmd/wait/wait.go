@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/constants"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/hooks"
 	"github.com/jenkins-x-plugins/jx-pipeline/pkg/triggers"
 	"github.com/jenkins-x/go-scm/scm"
 	jxc "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
@@ -29,6 +30,7 @@ import (
 // Options contains the command line options
 type Options struct {
 	options.BaseOptions
+	Hooks hooks.Options
 
 	WaitDuration        time.Duration
 	PollPeriod          time.Duration
@@ -77,6 +79,7 @@ func NewCmdPipelineWait() (*cobra.Command, *Options) {
 	cmd.Flags().DurationVarP(&o.WaitDuration, "duration", "", time.Minute*20, "Maximum duration to wait for one or more matching triggers to be setup in Lighthouse. Useful for when a new repository is being imported via GitOps")
 	cmd.Flags().DurationVarP(&o.PollPeriod, "poll-period", "", time.Second*2, "Poll period when waiting for one or more matching triggers to be setup in Lighthouse. Useful for when a new repository is being imported via GitOps")
 
+	o.Hooks.AddFlags(cmd)
 	o.BaseOptions.AddBaseFlags(cmd)
 	return cmd, o
 }
@@ -126,6 +129,16 @@ func (o *Options) Run() error {
 	}
 
 	log.Logger().Infof("the repository %s is now setup in lighthouse and has its webhook enabled", info(fullName))
+
+	err = o.Hooks.Run(&hooks.Event{
+		Hook:       hooks.PostWait,
+		Namespace:  o.Namespace,
+		Owner:      o.Owner,
+		Repository: o.Repository,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to run hooks after waiting for %s", fullName)
+	}
 	return nil
 }
 
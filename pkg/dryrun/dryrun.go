@@ -0,0 +1,200 @@
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// None the changes are made
+	None = "none"
+
+	// Client the changes are only displayed and not sent to the server
+	Client = "client"
+
+	// Server the changes are sent to the server as a dry run request so that they are validated but not persisted
+	Server = "server"
+
+	// Create the action of creating a resource
+	Create = "create"
+
+	// Update the action of updating a resource
+	Update = "update"
+
+	// Delete the action of deleting a resource
+	Delete = "delete"
+)
+
+var info = termcolor.ColorInfo
+
+// Options the dry run and diff options for commands which modify resources
+type Options struct {
+	DryRun string
+	Diff   bool
+	Out    io.Writer
+}
+
+// AddFlags adds CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.DryRun, "dry-run", "", None, "Must be 'none', 'server' or 'client'. If 'client' the changes are only displayed. If 'server' the changes are submitted as a server side dry run request without being persisted")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = Client
+	cmd.Flags().BoolVarP(&o.Diff, "diff", "", false, "Displays the differences in the resources which are created, changed or deleted")
+}
+
+// Validate verifies the options
+func (o *Options) Validate() error {
+	switch o.DryRun {
+	case "":
+		o.DryRun = None
+	case None, Client, Server:
+	default:
+		return options.InvalidOptionf("dry-run", o.DryRun, "must be one of: %s", strings.Join([]string{None, Client, Server}, ", "))
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	return nil
+}
+
+// IsDryRun returns true if the changes should not be persisted
+func (o *Options) IsDryRun() bool {
+	return o.IsClient() || o.IsServer()
+}
+
+// IsClient returns true if the changes should only be displayed
+func (o *Options) IsClient() bool {
+	return o.DryRun == Client
+}
+
+// IsServer returns true if the changes should be submitted as a server side dry run
+func (o *Options) IsServer() bool {
+	return o.DryRun == Server
+}
+
+// CreateOptions returns the options to create a resource
+func (o *Options) CreateOptions() metav1.CreateOptions {
+	return metav1.CreateOptions{DryRun: o.serverDryRun()}
+}
+
+// UpdateOptions returns the options to update a resource
+func (o *Options) UpdateOptions() metav1.UpdateOptions {
+	return metav1.UpdateOptions{DryRun: o.serverDryRun()}
+}
+
+// DeleteOptions returns the options to delete a resource
+func (o *Options) DeleteOptions() metav1.DeleteOptions {
+	return metav1.DeleteOptions{DryRun: o.serverDryRun()}
+}
+
+func (o *Options) serverDryRun() []string {
+	if o.IsServer() {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// Report logs the change which would be made in a dry run and displays the differences if enabled
+func (o *Options) Report(action, kind, name, ns string, oldResource, newResource interface{}) error {
+	log.Logger().Infof("would %s %s %s in namespace %s (dry run %s)", action, kind, info(name), info(ns), o.DryRun)
+	return o.PrintDiff(kind, name, oldResource, newResource)
+}
+
+// PrintDiff displays the differences between the old and new resource if enabled
+func (o *Options) PrintDiff(kind, name string, oldResource, newResource interface{}) error {
+	if !o.Diff {
+		return nil
+	}
+	text, err := Diff(kind+"/"+name, oldResource, newResource)
+	if err != nil {
+		return errors.Wrapf(err, "failed to diff %s %s", kind, name)
+	}
+	out := o.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, err = fmt.Fprint(out, text)
+	return err
+}
+
+// Diff returns the differences between the YAML of the old and new resources.
+// Either resource can be nil for a resource being created or deleted
+func Diff(name string, oldResource, newResource interface{}) (string, error) {
+	oldLines, err := toLines(oldResource)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal old resource")
+	}
+	newLines, err := toLines(newResource)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal new resource")
+	}
+
+	// lets find the longest common subsequence of lines
+	n := len(oldLines)
+	m := len(newLines)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case oldLines[i] == newLines[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString("--- " + name + "\n")
+	buf.WriteString("+++ " + name + "\n")
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldLines[i] == newLines[j]:
+			buf.WriteString("  " + oldLines[i] + "\n")
+			i++
+			j++
+		case i < n && (j >= m || lcs[i+1][j] >= lcs[i][j+1]):
+			buf.WriteString("- " + oldLines[i] + "\n")
+			i++
+		default:
+			buf.WriteString("+ " + newLines[j] + "\n")
+			j++
+		}
+	}
+	return buf.String(), nil
+}
+
+func toLines(resource interface{}) ([]string, error) {
+	if isNil(resource) {
+		return nil, nil
+	}
+	data, err := yaml.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+func isNil(resource interface{}) bool {
+	if resource == nil {
+		return true
+	}
+	v := reflect.ValueOf(resource)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
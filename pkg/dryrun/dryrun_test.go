@@ -0,0 +1,66 @@
+package dryrun_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/dryrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiff(t *testing.T) {
+	oldCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "config",
+		},
+		Data: map[string]string{
+			"a": "1",
+			"b": "2",
+		},
+	}
+	newCM := oldCM.DeepCopy()
+	newCM.Data["b"] = "3"
+
+	text, err := dryrun.Diff("ConfigMap/config", oldCM, newCM)
+	require.NoError(t, err, "failed to diff")
+
+	expected := `--- ConfigMap/config
++++ ConfigMap/config
+  data:
+    a: "1"
+-   b: "2"
++   b: "3"
+  metadata:
+    creationTimestamp: null
+    name: config
+`
+	assert.Equal(t, expected, text, "diff")
+
+	var nilCM *corev1.ConfigMap
+	text, err = dryrun.Diff("ConfigMap/config", nilCM, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}})
+	require.NoError(t, err, "failed to diff")
+	assert.Equal(t, "--- ConfigMap/config\n+++ ConfigMap/config\n+ metadata:\n+   creationTimestamp: null\n+   name: config\n", text, "diff of created resource")
+}
+
+func TestOptions(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := &dryrun.Options{DryRun: dryrun.Server, Diff: true, Out: out}
+	require.NoError(t, o.Validate(), "failed to validate")
+
+	assert.True(t, o.IsDryRun(), "IsDryRun")
+	assert.Equal(t, []string{metav1.DryRunAll}, o.CreateOptions().DryRun, "server side dry run create options")
+
+	err := o.PrintDiff("ConfigMap", "config", nil, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}})
+	require.NoError(t, err, "failed to print diff")
+	assert.Contains(t, out.String(), "+   name: config", "diff output")
+
+	o = &dryrun.Options{DryRun: dryrun.Client}
+	require.NoError(t, o.Validate(), "failed to validate")
+	assert.Empty(t, o.UpdateOptions().DryRun, "client side dry run should not send requests to the server")
+
+	o = &dryrun.Options{DryRun: "cheese"}
+	require.Error(t, o.Validate(), "should fail to validate an invalid dry run mode")
+}
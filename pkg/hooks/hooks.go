@@ -0,0 +1,149 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/apis/lighthouse/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// BinaryPrefix the prefix of the executables on the PATH which are invoked for a hook
+	BinaryPrefix = "jx-pipeline-"
+
+	// PostEffective the hook invoked after an effective pipeline has been rendered
+	PostEffective = "post-effective"
+
+	// PreStart the hook invoked before a LighthouseJob is created to start a pipeline
+	PreStart = "pre-start"
+
+	// PostWait the hook invoked by the wait command once a repository has been setup in lighthouse with its webhook enabled
+	PostWait = "post-wait"
+
+	// PostRun the hook invoked by the log command once the PipelineRun it is following has completed
+	PostRun = "post-run"
+
+	// StatusSucceeded the status of a PipelineRun which succeeded
+	StatusSucceeded = "Succeeded"
+
+	// StatusFailed the status of a PipelineRun which failed or was cancelled
+	StatusFailed = "Failed"
+
+	// StatusRunning the status of a PipelineRun which has not yet completed
+	StatusRunning = "Running"
+)
+
+// Event the JSON payload passed to a hook on stdin
+type Event struct {
+	Hook          string                     `json:"hook"`
+	Namespace     string                     `json:"namespace,omitempty"`
+	Owner         string                     `json:"owner,omitempty"`
+	Repository    string                     `json:"repository,omitempty"`
+	Branch        string                     `json:"branch,omitempty"`
+	Context       string                     `json:"context,omitempty"`
+	Path          string                     `json:"path,omitempty"`
+	TriggerPath   string                     `json:"triggerPath,omitempty"`
+	Status        string                     `json:"status,omitempty"`
+	PipelineRun   *tektonv1beta1.PipelineRun `json:"pipelineRun,omitempty"`
+	LighthouseJob *v1alpha1.LighthouseJob    `json:"lighthouseJob,omitempty"`
+}
+
+// Options the options for invoking hooks
+type Options struct {
+	Scripts       []string
+	Disabled      bool
+	CommandRunner cmdrunner.CommandRunner
+	LookPath      func(file string) (string, error)
+}
+
+// AddFlags adds CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&o.Scripts, "hook", "", nil, "A hook script to invoke of the form 'hookName=path'. If not specified the 'jx-pipeline-<hookName>' executable on the PATH is used if present.")
+	cmd.Flags().BoolVarP(&o.Disabled, "no-hooks", "", false, "Disables invoking any hooks")
+}
+
+// Run invokes the hook with the given event if there is a configured script or an executable on the PATH.
+// If the hook fails an error is returned so that hooks can be used to enforce policies
+func (o *Options) Run(event *Event) error {
+	if o.Disabled || event == nil {
+		return nil
+	}
+	name := event.Hook
+	path, err := o.FindHook(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find hook %s", name)
+	}
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal event for hook %s", name)
+	}
+
+	if o.CommandRunner == nil {
+		o.CommandRunner = cmdrunner.QuietCommandRunner
+	}
+	// lets write any hook output to stderr so it is not mixed up with the output of commands like effective
+	c := &cmdrunner.Command{
+		Name: path,
+		In:   bytes.NewReader(data),
+		Out:  os.Stderr,
+		Err:  os.Stderr,
+	}
+	log.Logger().Debugf("invoking hook %s via: %s", name, c.CLI())
+	_, err = o.CommandRunner(c)
+	if err != nil {
+		return errors.Wrapf(err, "hook %s failed", name)
+	}
+	return nil
+}
+
+// PipelineRunStatus returns the status of the PipelineRun passed to the post-run hook
+func PipelineRunStatus(pr *tektonv1beta1.PipelineRun) string {
+	c := pr.Status.GetCondition(apis.ConditionSucceeded)
+	switch {
+	case c == nil:
+		return StatusRunning
+	case c.IsTrue():
+		return StatusSucceeded
+	case c.IsFalse():
+		return StatusFailed
+	default:
+		return StatusRunning
+	}
+}
+
+// FindHook returns the path of the script or executable for the given hook name or an empty string if there is none
+func (o *Options) FindHook(name string) (string, error) {
+	for _, s := range o.Scripts {
+		paths := strings.SplitN(s, "=", 2)
+		if len(paths) != 2 {
+			return "", options.InvalidOptionf("hook", s, "should be of the form 'hookName=path'")
+		}
+		if paths[0] == name {
+			return paths[1], nil
+		}
+	}
+
+	if o.LookPath == nil {
+		o.LookPath = exec.LookPath
+	}
+	path, err := o.LookPath(BinaryPrefix + name)
+	if err != nil {
+		// no executable on the PATH so lets ignore this hook
+		return "", nil
+	}
+	return path, nil
+}
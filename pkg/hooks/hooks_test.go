@@ -0,0 +1,112 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/hooks"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+func TestHooks(t *testing.T) {
+	var commands []*cmdrunner.Command
+	var events []*hooks.Event
+
+	o := &hooks.Options{
+		Scripts: []string{"pre-start=/scripts/check.sh", "post-run=/scripts/notify.sh"},
+		CommandRunner: func(c *cmdrunner.Command) (string, error) {
+			commands = append(commands, c)
+			data, err := ioutil.ReadAll(c.In)
+			require.NoError(t, err, "failed to read stdin")
+			event := &hooks.Event{}
+			err = json.Unmarshal(data, event)
+			require.NoError(t, err, "failed to unmarshal stdin %s", string(data))
+			events = append(events, event)
+			return "", nil
+		},
+		LookPath: func(file string) (string, error) {
+			if file == "jx-pipeline-post-effective" {
+				return "/usr/bin/jx-pipeline-post-effective", nil
+			}
+			return "", errors.Errorf("executable file not found in $PATH")
+		},
+	}
+
+	err := o.Run(&hooks.Event{Hook: hooks.PreStart, Owner: "myorg", Repository: "myrepo"})
+	require.NoError(t, err, "failed to run hook %s", hooks.PreStart)
+
+	err = o.Run(&hooks.Event{Hook: hooks.PostEffective, Path: "release.yaml"})
+	require.NoError(t, err, "failed to run hook %s", hooks.PostEffective)
+
+	err = o.Run(&hooks.Event{Hook: hooks.PostWait})
+	require.NoError(t, err, "failed to run hook %s", hooks.PostWait)
+
+	err = o.Run(&hooks.Event{Hook: hooks.PostRun, Status: hooks.StatusFailed})
+	require.NoError(t, err, "failed to run hook %s", hooks.PostRun)
+
+	require.Len(t, commands, 3, "should have invoked 3 hooks")
+	assert.Equal(t, "/scripts/check.sh", commands[0].Name, "command for %s", hooks.PreStart)
+	assert.Equal(t, "myorg", events[0].Owner, "event.Owner for %s", hooks.PreStart)
+	assert.Equal(t, "myrepo", events[0].Repository, "event.Repository for %s", hooks.PreStart)
+	assert.Equal(t, "/usr/bin/jx-pipeline-post-effective", commands[1].Name, "command for %s", hooks.PostEffective)
+	assert.Equal(t, hooks.PostEffective, events[1].Hook, "event.Hook for %s", hooks.PostEffective)
+	assert.Equal(t, "release.yaml", events[1].Path, "event.Path for %s", hooks.PostEffective)
+	assert.Equal(t, "/scripts/notify.sh", commands[2].Name, "command for %s", hooks.PostRun)
+	assert.Equal(t, hooks.StatusFailed, events[2].Status, "event.Status for %s", hooks.PostRun)
+}
+
+func TestPipelineRunStatus(t *testing.T) {
+	testCases := []struct {
+		status   corev1.ConditionStatus
+		expected string
+	}{
+		{
+			status:   corev1.ConditionTrue,
+			expected: hooks.StatusSucceeded,
+		},
+		{
+			status:   corev1.ConditionFalse,
+			expected: hooks.StatusFailed,
+		},
+		{
+			status:   corev1.ConditionUnknown,
+			expected: hooks.StatusRunning,
+		},
+	}
+
+	for _, tc := range testCases {
+		pr := &v1beta1.PipelineRun{}
+		pr.Status.Conditions = duckv1beta1.Conditions{
+			{
+				Type:   apis.ConditionSucceeded,
+				Status: tc.status,
+			},
+		}
+		assert.Equal(t, tc.expected, hooks.PipelineRunStatus(pr), "status for condition %s", tc.status)
+	}
+
+	assert.Equal(t, hooks.StatusRunning, hooks.PipelineRunStatus(&v1beta1.PipelineRun{}), "status without a condition")
+}
+
+func TestHookFailure(t *testing.T) {
+	o := &hooks.Options{
+		Scripts: []string{"pre-start=/scripts/deny.sh"},
+		CommandRunner: func(c *cmdrunner.Command) (string, error) {
+			return "", errors.Errorf("exit status 1")
+		},
+	}
+	err := o.Run(&hooks.Event{Hook: hooks.PreStart})
+	require.Error(t, err, "should have failed the hook")
+
+	o.Disabled = true
+	err = o.Run(&hooks.Event{Hook: hooks.PreStart})
+	require.NoError(t, err, "should not invoke hooks when disabled")
+}
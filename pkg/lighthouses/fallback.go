@@ -0,0 +1,139 @@
+package lighthouses
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/filebrowser"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig/inrepo"
+)
+
+// VersionStreamRef the fallback ref which resolves to the version stream version of the repository
+const VersionStreamRef = "versionStream"
+
+var (
+	info = termcolor.ColorInfo
+
+	// pinnedRefRegex matches full commit SHAs and version tags which must never fall back to another ref
+	pinnedRefRegex = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64}|refs/tags/.+|v?[0-9]+(\.[0-9]+)+.*)$`)
+
+	// fileNotFoundMessages the error messages when a file does not exist at a ref
+	fileNotFoundMessages = []string{
+		"failed to find file ",
+		"does not exist in ",
+	}
+
+	// refNotFoundMessages the git error messages when fetching or checking out a ref which does not exist
+	// such as when a repository renames its default branch
+	refNotFoundMessages = []string{
+		"couldn't find remote ref ",
+		"did not match any file(s) known to git",
+		"unknown revision or path not in the working tree",
+		"invalid object name ",
+		"invalid reference: ",
+		"not a valid object name",
+	}
+)
+
+// FallbackFileBrowser a file browser which tries a list of fallback refs when a file cannot be found
+// such as when a catalog repository renames its default branch
+type FallbackFileBrowser struct {
+	filebrowser.Interface
+	Refs []string
+}
+
+// NewFallbackFileBrowser wraps the file browser so that the given refs are tried if a file cannot be found.
+// Empty refs are ignored so the underlying file browser is returned if there are no fallback refs
+func NewFallbackFileBrowser(fb filebrowser.Interface, refs []string) filebrowser.Interface {
+	var fallbackRefs []string
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref != "" {
+			fallbackRefs = append(fallbackRefs, ref)
+		}
+	}
+	if len(fallbackRefs) == 0 {
+		return fb
+	}
+	return &FallbackFileBrowser{
+		Interface: fb,
+		Refs:      fallbackRefs,
+	}
+}
+
+// GetFile returns the file at the given ref or the first fallback ref which contains the file.
+// The fallback refs are only tried if the file does not exist at a branch ref. Other errors such as
+// authentication or rate limit failures and pinned SHAs or tags never fall back so that pipelines stay reproducible
+func (f *FallbackFileBrowser) GetFile(owner, repo, path, ref string, fc filebrowser.FetchCache) ([]byte, error) {
+	data, err := f.Interface.GetFile(owner, repo, path, ref, fc)
+	if err == nil && len(data) > 0 {
+		return data, nil
+	}
+	if (err != nil && !IsNotFound(err)) || IsPinnedRef(ref) {
+		return data, err
+	}
+	tried := map[string]bool{ref: true}
+	for _, fallback := range f.Refs {
+		fallbackRef := f.resolveRef(owner, repo, fallback)
+		if fallbackRef == "" || tried[fallbackRef] {
+			continue
+		}
+		tried[fallbackRef] = true
+
+		fallbackData, fallbackErr := f.Interface.GetFile(owner, repo, path, fallbackRef, fc)
+		if fallbackErr != nil && !IsNotFound(fallbackErr) {
+			return nil, fallbackErr
+		}
+		if len(fallbackData) == 0 {
+			log.Logger().Debugf("could not find file %s in %s/%s at fallback ref %s", path, owner, repo, fallbackRef)
+			continue
+		}
+		label := fallbackRef
+		if fallbackRef != fallback {
+			label = fallback + " " + fallbackRef
+		}
+		log.Logger().Infof("could not find file %s in %s/%s at ref %s so using fallback ref %s", info(path), owner, repo, info(ref), info(label))
+		return fallbackData, nil
+	}
+	return data, err
+}
+
+// resolveRef returns the git ref for the fallback ref
+func (f *FallbackFileBrowser) resolveRef(owner, repo, ref string) string {
+	if ref == VersionStreamRef {
+		return inrepo.VersionStreamVersions[owner+"/"+repo]
+	}
+	return ref
+}
+
+// IsNotFound returns true if the error is caused by the file or ref not existing
+func IsNotFound(err error) bool {
+	return IsFileNotFound(err) || IsRefNotFound(err)
+}
+
+// IsFileNotFound returns true if the error is caused by the file not existing
+func IsFileNotFound(err error) bool {
+	return scmhelpers.IsScmNotFound(err) || containsAny(err.Error(), fileNotFoundMessages)
+}
+
+// IsRefNotFound returns true if the error is caused by git failing to fetch or checkout a ref which does not exist
+func IsRefNotFound(err error) bool {
+	return containsAny(err.Error(), refNotFoundMessages)
+}
+
+func containsAny(text string, values []string) bool {
+	for _, v := range values {
+		if strings.Contains(text, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPinnedRef returns true if the ref is a full commit SHA or a tag which should never fall back to another ref
+func IsPinnedRef(ref string) bool {
+	return pinnedRefRegex.MatchString(ref)
+}
@@ -0,0 +1,212 @@
+package lighthouses_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/lighthouses"
+	"github.com/jenkins-x/lighthouse-client/pkg/filebrowser"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig/inrepo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refFileBrowser a fake file browser which only contains files at the given refs
+type refFileBrowser struct {
+	filebrowser.Interface
+	files     map[string]string
+	failure   error
+	requested []string
+}
+
+func (f *refFileBrowser) GetFile(owner, repo, path, ref string, fc filebrowser.FetchCache) ([]byte, error) {
+	f.requested = append(f.requested, ref)
+	if f.failure != nil {
+		return nil, f.failure
+	}
+	text, ok := f.files[ref]
+	if !ok {
+		return nil, errors.Errorf("failed to find file %s at ref %s", path, ref)
+	}
+	return []byte(text), nil
+}
+
+// gitFileBrowser a file browser which clones a local git repository and checks out the ref like the lighthouse git file browser
+// so that the fallback is tested against the real git errors for missing refs
+type gitFileBrowser struct {
+	filebrowser.Interface
+	remote string
+	dir    string
+}
+
+func (f *gitFileBrowser) GetFile(owner, repo, path, ref string, fc filebrowser.FetchCache) ([]byte, error) {
+	err := runGit(f.dir, "checkout", ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to checkout %s at ref %s", f.remote, ref)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(f.dir, path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func runGit(dir string, args ...string) error {
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	c.Env = append(os.Environ(), "GIT_AUTHOR_NAME=jx", "GIT_AUTHOR_EMAIL=jx@example.com", "GIT_COMMITTER_NAME=jx", "GIT_COMMITTER_EMAIL=jx@example.com")
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "failed to run git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func TestFallbackFileBrowserWithGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(tmpDir)
+
+	// lets create a catalog which has renamed its default branch to main
+	remote := filepath.Join(tmpDir, "mycatalog")
+	path := "tasks/build.yaml"
+	require.NoError(t, os.MkdirAll(filepath.Join(remote, "tasks"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(remote, path), []byte("from main"), 0600))
+	require.NoError(t, runGit(remote, "init", "-q"))
+	require.NoError(t, runGit(remote, "checkout", "-q", "-b", "main"))
+	require.NoError(t, runGit(remote, "add", "."))
+	require.NoError(t, runGit(remote, "commit", "-q", "-m", "initial commit"))
+
+	dir := filepath.Join(tmpDir, "clone")
+	require.NoError(t, runGit(tmpDir, "clone", "-q", remote, dir))
+
+	fb := &gitFileBrowser{remote: remote, dir: dir}
+
+	_, err = fb.GetFile("myorg", "mycatalog", path, "master", nil)
+	require.Error(t, err, "should fail to checkout a missing branch")
+	assert.True(t, lighthouses.IsRefNotFound(err), "should be a missing ref error: %s", err.Error())
+
+	data, err := lighthouses.NewFallbackFileBrowser(fb, []string{"main"}).GetFile("myorg", "mycatalog", path, "master", nil)
+	require.NoError(t, err, "should have fallen back to main")
+	assert.Equal(t, "from main", string(data), "file from the fallback ref")
+
+	_, err = lighthouses.NewFallbackFileBrowser(fb, []string{"main"}).GetFile("myorg", "mycatalog", path, "v1.2.3", nil)
+	require.Error(t, err, "should not fall back for a missing tag")
+}
+
+func TestFallbackFileBrowser(t *testing.T) {
+	owner := "myorg"
+	repo := "mycatalog"
+	fullName := owner + "/" + repo
+	oldVersion, hasOldVersion := inrepo.VersionStreamVersions[fullName]
+	defer func() {
+		if hasOldVersion {
+			inrepo.VersionStreamVersions[fullName] = oldVersion
+		} else {
+			delete(inrepo.VersionStreamVersions, fullName)
+		}
+	}()
+	inrepo.VersionStreamVersions[fullName] = "abc123"
+
+	fallbackRefs := []string{lighthouses.VersionStreamRef, "main", "master"}
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	testCases := []struct {
+		name      string
+		ref       string
+		files     map[string]string
+		failure   error
+		refs      []string
+		expected  string
+		requested []string
+		hasError  bool
+	}{
+		{
+			name:      "found at ref",
+			ref:       "master",
+			files:     map[string]string{"master": "from master"},
+			refs:      fallbackRefs,
+			expected:  "from master",
+			requested: []string{"master"},
+		},
+		{
+			name:      "renamed default branch",
+			ref:       "master",
+			files:     map[string]string{"main": "from main"},
+			refs:      fallbackRefs,
+			expected:  "from main",
+			requested: []string{"master", "abc123", "main"},
+		},
+		{
+			name:      "version stream",
+			ref:       "master",
+			files:     map[string]string{"abc123": "from version stream"},
+			refs:      fallbackRefs,
+			expected:  "from version stream",
+			requested: []string{"master", "abc123"},
+		},
+		{
+			name:      "pinned tag",
+			ref:       "v1.2.3",
+			files:     map[string]string{"main": "from main"},
+			refs:      fallbackRefs,
+			requested: []string{"v1.2.3"},
+			hasError:  true,
+		},
+		{
+			name:      "pinned sha",
+			ref:       sha,
+			files:     map[string]string{"main": "from main"},
+			refs:      fallbackRefs,
+			requested: []string{sha},
+			hasError:  true,
+		},
+		{
+			name:      "transient failure",
+			ref:       "master",
+			files:     map[string]string{"main": "from main"},
+			failure:   errors.New("API rate limit exceeded"),
+			refs:      fallbackRefs,
+			requested: []string{"master"},
+			hasError:  true,
+		},
+		{
+			name:      "not found",
+			ref:       "master",
+			files:     map[string]string{},
+			refs:      fallbackRefs,
+			requested: []string{"master", "abc123", "main"},
+			hasError:  true,
+		},
+		{
+			name:      "disabled",
+			ref:       "master",
+			files:     map[string]string{"main": "from main"},
+			refs:      []string{""},
+			requested: []string{"master"},
+			hasError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		fake := &refFileBrowser{files: tc.files, failure: tc.failure}
+		fb := lighthouses.NewFallbackFileBrowser(fake, tc.refs)
+
+		data, err := fb.GetFile(owner, repo, "tasks/build.yaml", tc.ref, nil)
+		if tc.hasError {
+			require.Error(t, err, "should have failed for %s", tc.name)
+		} else {
+			require.NoError(t, err, "failed for %s", tc.name)
+			assert.Equal(t, tc.expected, string(data), "file for %s", tc.name)
+		}
+		assert.Equal(t, tc.requested, fake.requested, "requested refs for %s", tc.name)
+	}
+}
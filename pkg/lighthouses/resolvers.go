@@ -22,6 +22,7 @@ type ResolverOptions struct {
 	CatalogOwner      string
 	CatalogRepository string
 	CatalogSHA        string
+	FallbackRefs      []string
 }
 
 // AddFlags adds CLI flags
@@ -31,6 +32,7 @@ func (o *ResolverOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "The directory to look for the .lighthouse and/or .git folders")
 	cmd.Flags().StringVarP(&o.CatalogOwner, "catalog-owner", "", "jenkins-x", "The github owner for the default catalog")
 	cmd.Flags().StringVarP(&o.CatalogRepository, "catalog-repo", "", "jx3-pipeline-catalog", "The github repository name for the default catalog")
+	cmd.Flags().StringArrayVarP(&o.FallbackRefs, "fallback-ref", "", nil, "The refs to try in order if a file in a 'uses:' expression does not exist at its branch ref such as when a repository renames its default branch. The value 'versionStream' uses the version stream ref of the repository. e.g. --fallback-ref versionStream --fallback-ref main. Pinned SHAs and tags never fall back. Disabled by default")
 }
 
 // CreateResolver creates the resolver from the available options
@@ -84,6 +86,7 @@ func (o *ResolverOptions) CreateResolver() (*inrepo.UsesResolver, error) {
 		}
 		fb = filebrowser.NewFileBrowserFromGitClient(gitFactory)
 	}
+	fb = NewFallbackFileBrowser(fb, o.FallbackRefs)
 
 	fileBrowsers, err := filebrowser.NewFileBrowsers(f.GitServerURL, fb)
 	if err != nil {
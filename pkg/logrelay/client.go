@@ -0,0 +1,106 @@
+package logrelay
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientOptions the options to connect to the relay
+type ClientOptions struct {
+	// Address the host:port of the relay
+	Address string
+
+	// Token the bearer token to authenticate with
+	Token string
+
+	// Insecure uses a plain text connection rather than TLS
+	Insecure bool
+}
+
+// Client a client of the relay which uses a single connection for all requests
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient connects to the relay. Any HTTPS_PROXY environment variable is used to connect through a proxy
+func NewClient(ctx context.Context, o *ClientOptions, opts ...grpc.DialOption) (*Client, error) {
+	if o.Insecure {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
+	}
+	if o.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{token: o.Token, insecure: o.Insecure}))
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)))
+
+	conn, err := grpc.DialContext(ctx, o.Address, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the log relay at %s", o.Address)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ListBuilds returns the names of the builds matching the filter
+func (c *Client) ListBuilds(ctx context.Context, req *BuildsRequest) (*BuildsResponse, error) {
+	answer := &BuildsResponse{}
+	err := c.conn.Invoke(ctx, listBuildsMethod, req, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list builds")
+	}
+	return answer, nil
+}
+
+// StreamLogs writes the log lines of the build to the output
+func (c *Client) StreamLogs(ctx context.Context, req *LogsRequest, out io.Writer) error {
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], streamLogsMethod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the log stream")
+	}
+	err = stream.SendMsg(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to request the logs of %s", req.Name)
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		return errors.Wrapf(err, "failed to close the log request")
+	}
+	for {
+		line := &LogLine{}
+		err = stream.RecvMsg(line)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to receive the logs of %s", req.Name)
+		}
+		fmt.Fprintln(out, line.Line)
+	}
+}
+
+// tokenCredentials adds the bearer token to each request
+type tokenCredentials struct {
+	token    string
+	insecure bool
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + c.token,
+	}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return !c.insecure
+}
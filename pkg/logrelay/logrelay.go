@@ -0,0 +1,142 @@
+package logrelay
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// ServiceName the name of the gRPC service
+	ServiceName = "jxpipeline.LogRelay"
+
+	// CodecName the name of the codec used to marshal the messages so that no generated protobuf code is required.
+	// It is specific to this package so that registering it does not replace any other JSON codec in the process
+	CodecName = "jxpipeline-json"
+
+	// DefaultPort the default port of the relay
+	DefaultPort = 8443
+
+	// TokenEnvVar the environment variable used to default the token used to authenticate with the relay
+	TokenEnvVar = "JX_PIPELINE_LOG_RELAY_TOKEN"
+
+	listBuildsMethod = "/" + ServiceName + "/ListBuilds"
+	streamLogsMethod = "/" + ServiceName + "/StreamLogs"
+)
+
+// BuildsRequest requests the names of the builds matching the filter
+type BuildsRequest struct {
+	Filter tektonlog.BuildPodInfoFilter `json:"filter"`
+}
+
+// BuildsResponse the names of the matching builds, newest first
+type BuildsResponse struct {
+	Names []string `json:"names,omitempty"`
+}
+
+// LogsRequest requests the logs of the build with the given name
+type LogsRequest struct {
+	Name           string `json:"name"`
+	FailIfPodFails bool   `json:"failIfPodFails,omitempty"`
+}
+
+// LogLine a line of a build log
+type LogLine struct {
+	Line string `json:"line"`
+}
+
+// LogRelayServer the server side of the relay
+type LogRelayServer interface {
+	// ListBuilds returns the names of the builds matching the filter
+	ListBuilds(ctx context.Context, req *BuildsRequest) (*BuildsResponse, error)
+
+	// StreamLogs streams the log of a build
+	StreamLogs(req *LogsRequest, stream LogLineSender) error
+}
+
+// LogLineSender sends log lines to the client
+type LogLineSender interface {
+	Send(line *LogLine) error
+	Context() context.Context
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*LogRelayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBuilds",
+			Handler:    listBuildsHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogs",
+			Handler:       streamLogsHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// RegisterLogRelayServer registers the relay with the gRPC server
+func RegisterLogRelayServer(s *grpc.Server, srv LogRelayServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+func listBuildsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &BuildsRequest{}
+	err := dec(req)
+	if err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogRelayServer).ListBuilds(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: listBuildsMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogRelayServer).ListBuilds(ctx, req.(*BuildsRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func streamLogsHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &LogsRequest{}
+	err := stream.RecvMsg(req)
+	if err != nil {
+		return err
+	}
+	return srv.(LogRelayServer).StreamLogs(req, &logLineSender{stream})
+}
+
+type logLineSender struct {
+	grpc.ServerStream
+}
+
+func (s *logLineSender) Send(line *LogLine) error {
+	return s.ServerStream.SendMsg(line)
+}
+
+// jsonCodec marshals the messages as JSON
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
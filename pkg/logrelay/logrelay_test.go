@@ -0,0 +1,84 @@
+package logrelay_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/logrelay"
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	faketekton "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLogRelayListBuilds(t *testing.T) {
+	ns := "jx"
+	token := "mytoken"
+	now := metav1.Now()
+
+	server := &logrelay.Server{
+		KubeClient:   fake.NewSimpleClientset(),
+		TektonClient: faketekton.NewSimpleClientset(),
+		JXClient: fakejx.NewSimpleClientset(
+			&v1.PipelineActivity{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "myorg-myrepo-main-1",
+					Namespace: ns,
+				},
+				Spec: v1.PipelineActivitySpec{
+					Build:              "1",
+					GitOwner:           "myorg",
+					GitRepository:      "myrepo",
+					GitBranch:          "main",
+					Context:            "release",
+					CompletedTimestamp: &now,
+				},
+			},
+		),
+		Namespace: ns,
+		Token:     token,
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	gs := server.NewGRPCServer()
+	go func() {
+		_ = gs.Serve(listener)
+	}()
+	defer gs.Stop()
+
+	ctx := context.TODO()
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	})
+	req := &logrelay.BuildsRequest{
+		Filter: tektonlog.BuildPodInfoFilter{
+			Repository: "myrepo",
+		},
+	}
+
+	client, err := logrelay.NewClient(ctx, &logrelay.ClientOptions{Address: "bufnet", Token: token, Insecure: true}, dialer)
+	require.NoError(t, err, "failed to create client")
+	defer client.Close()
+
+	resp, err := client.ListBuilds(ctx, req)
+	require.NoError(t, err, "failed to list builds")
+	assert.Equal(t, []string{"myorg/myrepo/main #1 release"}, resp.Names, "build names")
+
+	badClient, err := logrelay.NewClient(ctx, &logrelay.ClientOptions{Address: "bufnet", Token: "wrong", Insecure: true}, dialer)
+	require.NoError(t, err, "failed to create client")
+	defer badClient.Close()
+
+	_, err = badClient.ListBuilds(ctx, req)
+	require.Error(t, err, "should fail to list builds with an invalid token")
+	assert.Equal(t, codes.Unauthenticated, status.Code(errors.Cause(err)), "error code")
+}
@@ -0,0 +1,130 @@
+package logrelay
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/tektonlog"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Server streams the build logs from inside the cluster
+type Server struct {
+	KubeClient   kubernetes.Interface
+	JXClient     versioned.Interface
+	TektonClient tektonclient.Interface
+	Namespace    string
+
+	// Token the bearer token clients must present
+	Token string
+}
+
+// NewGRPCServer creates a gRPC server for the relay which authenticates each request using the token
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			err := s.authenticate(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := s.authenticate(ss.Context())
+			if err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	gs := grpc.NewServer(opts...)
+	RegisterLogRelayServer(gs, s)
+	return gs
+}
+
+// ListBuilds returns the names of the builds matching the filter
+func (s *Server) ListBuilds(ctx context.Context, req *BuildsRequest) (*BuildsResponse, error) {
+	names, _, _, err := s.newLogger(false).GetTektonPipelinesWithActivePipelineActivity(ctx, &req.Filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find builds: %s", err.Error())
+	}
+
+	filter := strings.ToLower(req.Filter.Filter)
+	answer := &BuildsResponse{}
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), filter) {
+			answer.Names = append(answer.Names, name)
+		}
+	}
+	return answer, nil
+}
+
+// StreamLogs streams the log of a build
+func (s *Server) StreamLogs(req *LogsRequest, stream LogLineSender) error {
+	ctx := stream.Context()
+	logger := s.newLogger(req.FailIfPodFails)
+	_, paMap, prMap, err := logger.GetTektonPipelinesWithActivePipelineActivity(ctx, nil)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to find builds: %s", err.Error())
+	}
+	pa := paMap[req.Name]
+	if pa == nil {
+		return status.Errorf(codes.NotFound, "there are no build logs for %s", req.Name)
+	}
+
+	log.Logger().Infof("streaming the logs of %s", req.Name)
+	err = logger.GetLogsForActivity(ctx, &lineWriter{stream: stream}, pa, req.Name, prMap[req.Name])
+	if err != nil {
+		return status.Errorf(codes.Unknown, "failed to get the logs of %s: %s", req.Name, err.Error())
+	}
+	return nil
+}
+
+// newLogger creates a logger for each request as the logger records the last streaming error
+func (s *Server) newLogger(failIfPodFails bool) *tektonlog.TektonLogger {
+	return &tektonlog.TektonLogger{
+		KubeClient:     s.KubeClient,
+		JXClient:       s.JXClient,
+		TektonClient:   s.TektonClient,
+		Namespace:      s.Namespace,
+		FailIfPodFails: failIfPodFails,
+	}
+}
+
+func (s *Server) authenticate(ctx context.Context) error {
+	if s.Token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid token")
+}
+
+// lineWriter sends each line written by the logger to the client
+type lineWriter struct {
+	stream LogLineSender
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSuffix(string(p), "\n")
+	for _, line := range strings.Split(text, "\n") {
+		err := w.stream.Send(&LogLine{Line: line})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
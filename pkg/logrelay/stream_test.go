@@ -0,0 +1,77 @@
+package logrelay
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	faketekton "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// stubServer streams the given logs via the lineWriter used by the Server
+type stubServer struct {
+	Server
+	logs map[string]string
+}
+
+func (s *stubServer) StreamLogs(req *LogsRequest, stream LogLineSender) error {
+	text, ok := s.logs[req.Name]
+	if !ok {
+		return s.Server.StreamLogs(req, stream)
+	}
+	w := &lineWriter{stream: stream}
+	_, err := w.Write([]byte(text))
+	return err
+}
+
+func TestLogRelayStreamLogs(t *testing.T) {
+	name := "myorg/myrepo/main #1 release"
+	server := &stubServer{
+		Server: Server{
+			KubeClient:   fake.NewSimpleClientset(),
+			JXClient:     fakejx.NewSimpleClientset(),
+			TektonClient: faketekton.NewSimpleClientset(),
+			Namespace:    "jx",
+		},
+		logs: map[string]string{
+			name: "Showing logs for build\nbuild step 1\n\nbuild step 2\n",
+		},
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	RegisterLogRelayServer(gs, server)
+	go func() {
+		_ = gs.Serve(listener)
+	}()
+	defer gs.Stop()
+
+	ctx := context.TODO()
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	})
+	client, err := NewClient(ctx, &ClientOptions{Address: "bufnet", Insecure: true}, dialer)
+	require.NoError(t, err, "failed to create client")
+	defer client.Close()
+
+	out := &bytes.Buffer{}
+	err = client.StreamLogs(ctx, &LogsRequest{Name: name}, out)
+	require.NoError(t, err, "failed to stream logs")
+	assert.Equal(t, "Showing logs for build\nbuild step 1\n\nbuild step 2\n", out.String(), "streamed logs")
+
+	out.Reset()
+	err = client.StreamLogs(ctx, &LogsRequest{Name: "myorg/myrepo/main #2 release"}, out)
+	require.Error(t, err, "should fail to stream the logs of a build which does not exist")
+	assert.Equal(t, codes.NotFound, status.Code(errors.Cause(err)), "error code")
+	assert.Empty(t, out.String(), "streamed logs for a build which does not exist")
+}
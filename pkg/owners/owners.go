@@ -0,0 +1,172 @@
+package owners
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// AnnotationTeam the annotation on a trigger or pipeline for the team which owns it
+	AnnotationTeam = "pipeline.jenkins-x.io/owner-team"
+
+	// AnnotationChannel the annotation on a trigger or pipeline for the chat channel of the owning team
+	AnnotationChannel = "pipeline.jenkins-x.io/owner-channel"
+)
+
+// CodeOwnersPaths the paths relative to the repository root where a CODEOWNERS file can be found
+var CodeOwnersPaths = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// Owner the ownership metadata of a trigger or pipeline
+type Owner struct {
+	Team    string `json:"team,omitempty"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// FromAnnotations returns the owner from the given annotations or nil if there is none
+func FromAnnotations(annotations map[string]string) *Owner {
+	if annotations == nil {
+		return nil
+	}
+	o := &Owner{
+		Team:    annotations[AnnotationTeam],
+		Channel: annotations[AnnotationChannel],
+	}
+	if o.Team == "" && o.Channel == "" {
+		return nil
+	}
+	return o
+}
+
+// Annotations returns just the ownership annotations from the given annotations
+func Annotations(annotations map[string]string) map[string]string {
+	var answer map[string]string
+	for _, k := range []string{AnnotationTeam, AnnotationChannel} {
+		v := annotations[k]
+		if v != "" {
+			if answer == nil {
+				answer = map[string]string{}
+			}
+			answer[k] = v
+		}
+	}
+	return answer
+}
+
+// String returns a description of the owner for display in tables
+func (o *Owner) String() string {
+	if o == nil {
+		return ""
+	}
+	if o.Channel == "" {
+		return o.Team
+	}
+	if o.Team == "" {
+		return o.Channel
+	}
+	return o.Team + " " + o.Channel
+}
+
+// ToString returns a description of the owner in the given annotations
+func ToString(annotations map[string]string) string {
+	return FromAnnotations(annotations).String()
+}
+
+// CodeOwners the parsed rules in a CODEOWNERS file
+type CodeOwners struct {
+	Rules []*Rule
+}
+
+// Rule a single rule in a CODEOWNERS file
+type Rule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// LoadCodeOwners loads the CODEOWNERS file at the given path
+func LoadCodeOwners(path string) (*CodeOwners, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open file %s", path)
+	}
+	defer f.Close()
+
+	answer := &CodeOwners{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		var values []string
+		for _, v := range fields[1:] {
+			if strings.HasPrefix(v, "#") {
+				break
+			}
+			values = append(values, v)
+		}
+		answer.Rules = append(answer.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  values,
+			regex:   patternToRegex(fields[0]),
+		})
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %s", path)
+	}
+	return answer, nil
+}
+
+// Owners returns the owners of the given path relative to the repository root.
+// As with git the last matching rule takes precedence
+func (c *CodeOwners) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "./")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		r := c.Rules[i]
+		if r.regex != nil && r.regex.MatchString(path) {
+			return r.Owners
+		}
+	}
+	return nil
+}
+
+// patternToRegex converts a gitignore style CODEOWNERS pattern into a regular expression
+func patternToRegex(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	buf := strings.Builder{}
+	buf.WriteString("^")
+	if !anchored {
+		buf.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			buf.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			buf.WriteString(".*")
+			i++
+		case c == '*':
+			buf.WriteString("[^/]*")
+		case c == '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("(/.*)?$")
+	r, err := regexp.Compile(buf.String())
+	if err != nil {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,46 @@
+package owners_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-pipeline/pkg/owners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners(t *testing.T) {
+	path := filepath.Join("test_data", "CODEOWNERS")
+	co, err := owners.LoadCodeOwners(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	testCases := map[string][]string{
+		"README.md":                              {"@myorg/everyone"},
+		"docs/index.md":                          {"@myorg/docs"},
+		".lighthouse/jenkins-x/release.yaml":     {"@myorg/release", "@bob"},
+		".lighthouse/jenkins-x/pullrequest.yaml": {"@myorg/platform"},
+		"charts/myapp/templates/deployment.yaml": {"@myorg/charts"},
+		"src/main.go":                            {"@myorg/backend"},
+		"src/nested/deeply/handler.go":           {"@myorg/backend"},
+		"scripts/release.sh":                     {"@myorg/everyone"},
+	}
+	for file, expected := range testCases {
+		assert.Equal(t, expected, co.Owners(file), "owners of %s", file)
+	}
+}
+
+func TestOwnerAnnotations(t *testing.T) {
+	assert.Nil(t, owners.FromAnnotations(nil), "no annotations")
+	assert.Nil(t, owners.FromAnnotations(map[string]string{"foo": "bar"}), "no owner annotations")
+
+	annotations := map[string]string{
+		"foo":                    "bar",
+		owners.AnnotationTeam:    "@myorg/release",
+		owners.AnnotationChannel: "#release",
+	}
+	assert.Equal(t, "@myorg/release #release", owners.ToString(annotations), "owner description")
+	assert.Equal(t, map[string]string{
+		owners.AnnotationTeam:    "@myorg/release",
+		owners.AnnotationChannel: "#release",
+	}, owners.Annotations(annotations), "owner annotations")
+}
@@ -227,6 +227,7 @@ func ToPipelineActivity(pr *v1beta1.PipelineRun, pa *v1.PipelineActivity, overwr
 			}
 		}
 	}
+	steps = applySkippedStages(pr, steps)
 
 	if overwriteSteps {
 		for _, stage := range steps {
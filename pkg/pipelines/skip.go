@@ -0,0 +1,165 @@
+package pipelines
+
+import (
+	"sort"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+const (
+	// SkipStageAnnotationPrefix the prefix of the PipelineRun annotations which mark a stage as intentionally skipped.
+	// The rest of the key is the pipeline task name and the value is the reason such as 'docs only change'
+	SkipStageAnnotationPrefix = "skip.pipeline.jenkins-x.io/"
+
+	// SkipStagesParam the PipelineRun parameter containing a comma separated list of pipeline task names to skip
+	SkipStagesParam = "skip-stages"
+
+	// StatusSkipped the status displayed for skipped stages which is also the prefix of their description
+	StatusSkipped = "Skipped"
+
+	skipStagesParamReason = "skip-stages parameter"
+	whenExpressionsReason = "when expressions evaluated to false"
+)
+
+// SkipStageAnnotation returns the annotation key to mark the given pipeline task as skipped
+func SkipStageAnnotation(taskName string) string {
+	return SkipStageAnnotationPrefix + taskName
+}
+
+// SkippedStages returns the reason for each pipeline task which was intentionally skipped indexed by the task name.
+// Stages can be skipped via annotations, the skip-stages parameter or by tekton when expressions. Tasks listed in the
+// skip-stages parameter are ignored if they actually ran as the pipeline may not honor the parameter
+func SkippedStages(pr *v1beta1.PipelineRun) map[string]string {
+	ran := map[string]bool{}
+	for _, tr := range pr.Status.TaskRuns {
+		if tr != nil {
+			ran[tr.PipelineTaskName] = true
+		}
+	}
+
+	answer := map[string]string{}
+	for _, param := range pr.Spec.Params {
+		if param.Name != SkipStagesParam {
+			continue
+		}
+		for _, name := range strings.Split(param.Value.StringVal, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !ran[name] {
+				answer[name] = skipStagesParamReason
+			}
+		}
+	}
+	for _, st := range pr.Status.SkippedTasks {
+		answer[st.Name] = whenExpressionsReason
+	}
+	for k, v := range pr.Annotations {
+		if strings.HasPrefix(k, SkipStageAnnotationPrefix) {
+			name := strings.TrimPrefix(k, SkipStageAnnotationPrefix)
+			if name != "" {
+				answer[name] = v
+			}
+		}
+	}
+	return answer
+}
+
+// SkippedStageNames returns the sorted names of the skipped stages
+func SkippedStageNames(skipped map[string]string) []string {
+	var names []string
+	for name := range skipped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SkipReason returns the reason the step was skipped and true if the step was skipped
+func SkipReason(step *v1.CoreActivityStep) (string, bool) {
+	if step == nil || !strings.HasPrefix(step.Description, StatusSkipped) {
+		return "", false
+	}
+	reason := strings.TrimPrefix(step.Description, StatusSkipped)
+	return strings.TrimSpace(strings.TrimPrefix(reason, ":")), true
+}
+
+// ActivitySkippedStageNames returns the names of the skipped stages in the PipelineActivity
+func ActivitySkippedStageNames(pa *v1.PipelineActivity) []string {
+	var names []string
+	for _, step := range pa.Spec.Steps {
+		if step.Stage == nil {
+			continue
+		}
+		if _, ok := SkipReason(&step.Stage.CoreActivityStep); ok {
+			names = append(names, step.Stage.Name)
+		}
+	}
+	return names
+}
+
+// applySkippedStages marks the stages which were intentionally skipped so they are not confused with missing or failed stages
+func applySkippedStages(pr *v1beta1.PipelineRun, steps []v1.PipelineActivityStep) []v1.PipelineActivityStep {
+	skipped := SkippedStages(pr)
+	taskNames := pipelineTaskNames(pr)
+	for _, st := range pr.Status.SkippedTasks {
+		taskNames[st.Name] = true
+	}
+	for _, taskName := range SkippedStageNames(skipped) {
+		reason := skipped[taskName]
+		description := StatusSkipped
+		if reason != "" {
+			description += ": " + reason
+		}
+		stageName := strings.ReplaceAll(taskName, "-", " ")
+
+		found := false
+		for i := range steps {
+			stage := steps[i].Stage
+			if stage == nil || stage.Name != stageName {
+				continue
+			}
+			found = true
+			// a failure should never be hidden by a skip
+			if stage.Status != v1.ActivityStatusTypeFailed && stage.Status != v1.ActivityStatusTypeError {
+				stage.Description = description
+			}
+		}
+		// only add stages which are in the pipeline so that typos are not shown as succeeded stages
+		if !found && taskNames[taskName] {
+			steps = append(steps, v1.PipelineActivityStep{
+				Kind: v1.ActivityStepKindTypeStage,
+				Stage: &v1.StageActivityStep{
+					CoreActivityStep: v1.CoreActivityStep{
+						Name:        stageName,
+						Description: description,
+						// skipped stages are complete so they do not keep the pipeline running or fail it
+						Status:             v1.ActivityStatusTypeSucceeded,
+						StartedTimestamp:   pr.Status.StartTime,
+						CompletedTimestamp: pr.Status.StartTime,
+					},
+				},
+			})
+		}
+	}
+	return steps
+}
+
+// pipelineTaskNames returns the names of the tasks in the pipeline
+func pipelineTaskNames(pr *v1beta1.PipelineRun) map[string]bool {
+	answer := map[string]bool{}
+	ps := pr.Spec.PipelineSpec
+	if ps == nil {
+		ps = pr.Status.PipelineSpec
+	}
+	if ps == nil {
+		return answer
+	}
+	for i := range ps.Tasks {
+		answer[ps.Tasks[i].Name] = true
+	}
+	for i := range ps.Finally {
+		answer[ps.Finally[i].Name] = true
+	}
+	return answer
+}
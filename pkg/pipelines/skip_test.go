@@ -0,0 +1,130 @@
+package pipelines
+
+import (
+	"testing"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSkippedStages(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				SkipStageAnnotation("promote"): "docs only change",
+				"something/else":               "ignored",
+			},
+		},
+		Spec: v1beta1.PipelineRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  SkipStagesParam,
+					Value: *v1beta1.NewArrayOrString("lint, test"),
+				},
+			},
+		},
+		Status: v1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				SkippedTasks: []v1beta1.SkippedTask{
+					{Name: "release"},
+				},
+			},
+		},
+	}
+
+	skipped := SkippedStages(pr)
+	assert.Equal(t, map[string]string{
+		"lint":    skipStagesParamReason,
+		"test":    skipStagesParamReason,
+		"release": whenExpressionsReason,
+		"promote": "docs only change",
+	}, skipped)
+	assert.Equal(t, []string{"lint", "promote", "release", "test"}, SkippedStageNames(skipped))
+}
+
+func TestApplySkippedStages(t *testing.T) {
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				SkipStageAnnotation("from-build-pack"): "docs only change",
+				SkipStageAnnotation("promote-release"): "docs only change",
+				SkipStageAnnotation("verify"):          "",
+				SkipStageAnnotation("promote-relase"):  "typo",
+			},
+		},
+		Spec: v1beta1.PipelineRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  SkipStagesParam,
+					Value: *v1beta1.NewArrayOrString("build"),
+				},
+			},
+			PipelineSpec: &v1beta1.PipelineSpec{
+				Tasks: []v1beta1.PipelineTask{
+					{Name: "from-build-pack"},
+					{Name: "build"},
+					{Name: "verify"},
+					{Name: "promote-release"},
+				},
+			},
+		},
+		Status: v1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*v1beta1.PipelineRunTaskRunStatus{
+					"myrun-build-abc": {PipelineTaskName: "build"},
+				},
+			},
+		},
+	}
+	steps := []v1.PipelineActivityStep{
+		{
+			Kind: v1.ActivityStepKindTypeStage,
+			Stage: &v1.StageActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Name:   "from build pack",
+					Status: v1.ActivityStatusTypeSucceeded,
+				},
+			},
+		},
+		{
+			Kind: v1.ActivityStepKindTypeStage,
+			Stage: &v1.StageActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Name:   "build",
+					Status: v1.ActivityStatusTypeRunning,
+				},
+			},
+		},
+		{
+			Kind: v1.ActivityStepKindTypeStage,
+			Stage: &v1.StageActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Name:   "verify",
+					Status: v1.ActivityStatusTypeFailed,
+				},
+			},
+		},
+	}
+
+	steps = applySkippedStages(pr, steps)
+	pa := &v1.PipelineActivity{Spec: v1.PipelineActivitySpec{Steps: steps}}
+	assert.Equal(t, []string{"from build pack", "promote release"}, ActivitySkippedStageNames(pa))
+
+	reason, ok := SkipReason(&steps[0].Stage.CoreActivityStep)
+	assert.True(t, ok, "from build pack should be skipped")
+	assert.Equal(t, "docs only change", reason)
+
+	_, ok = SkipReason(&steps[1].Stage.CoreActivityStep)
+	assert.False(t, ok, "a stage which ran should not be skipped by the skip-stages parameter")
+
+	_, ok = SkipReason(&steps[2].Stage.CoreActivityStep)
+	assert.False(t, ok, "a failed stage should not be shown as skipped")
+
+	require.Len(t, steps, 4, "the misspelt stage should not be added")
+	added := steps[3].Stage
+	assert.Equal(t, "promote release", added.Name)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, added.Status)
+}
@@ -0,0 +1,48 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"knative.dev/pkg/apis"
+)
+
+// ValidatePipelineRun validates the PipelineRun and the volumes of its tasks
+func ValidatePipelineRun(ctx context.Context, pr *v1beta1.PipelineRun) *apis.FieldError {
+	err := pr.Validate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// lets validate each TaskSpec has the right volumes etc
+	ps := pr.Spec.PipelineSpec
+	if ps == nil {
+		return nil
+	}
+	for i := range ps.Tasks {
+		pt := &ps.Tasks[i]
+		if pt.TaskSpec == nil {
+			continue
+		}
+		err = err.Also(ValidateTaskRunVolumesExist(&pt.TaskSpec.TaskSpec).ViaFieldIndex("tasks", i)).ViaField("spec", "pipelineSpec")
+	}
+	return err
+}
+
+// ValidateTaskRunVolumesExist validates the volume mounts of the steps refer to volumes of the task
+func ValidateTaskRunVolumesExist(ts *v1beta1.TaskSpec) (errs *apis.FieldError) {
+	volumeNames := map[string]bool{}
+	for _, v := range ts.Volumes {
+		volumeNames[v.Name] = true
+	}
+
+	for i, s := range ts.Steps {
+		for j, v := range s.VolumeMounts {
+			if !volumeNames[v.Name] {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Not found: %s", v.Name), "name").ViaFieldIndex("volumeMounts", j).ViaFieldIndex("steps", i).ViaField("taskSpec"))
+			}
+		}
+	}
+	return
+}
@@ -43,16 +43,23 @@ func PipelineRunIsComplete(pr *pipelineapi.PipelineRun) bool {
 
 // CancelPipelineRun cancels a Pipeline
 func CancelPipelineRun(ctx context.Context, tektonClient tektonclient.Interface, ns string, pr *pipelineapi.PipelineRun) error {
+	_, _, err := CancelPipelineRunWithOptions(ctx, tektonClient, ns, pr, metav1.UpdateOptions{})
+	return err
+}
+
+// CancelPipelineRunWithOptions cancels a Pipeline using the given update options such as a server side dry run
+// returning the current PipelineRun along with the cancelled PipelineRun returned by the server
+func CancelPipelineRunWithOptions(ctx context.Context, tektonClient tektonclient.Interface, ns string, pr *pipelineapi.PipelineRun, opts metav1.UpdateOptions) (*pipelineapi.PipelineRun, *pipelineapi.PipelineRun, error) {
 	prName := pr.Name
-	var err error
-	pr, err = tektonClient.TektonV1beta1().PipelineRuns(ns).Get(ctx, prName, metav1.GetOptions{})
+	current, err := tektonClient.TektonV1beta1().PipelineRuns(ns).Get(ctx, prName, metav1.GetOptions{})
 	if err != nil {
-		return errors.Wrapf(err, "failed to get PipelineRun %s in namespace %s", prName, ns)
+		return nil, nil, errors.Wrapf(err, "failed to get PipelineRun %s in namespace %s", prName, ns)
 	}
-	pr.Spec.Status = pipelineapi.PipelineRunSpecStatusCancelled
-	_, err = tektonClient.TektonV1beta1().PipelineRuns(ns).Update(ctx, pr, metav1.UpdateOptions{})
+	cancelled := current.DeepCopy()
+	cancelled.Spec.Status = pipelineapi.PipelineRunSpecStatusCancelled
+	cancelled, err = tektonClient.TektonV1beta1().PipelineRuns(ns).Update(ctx, cancelled, opts)
 	if err != nil {
-		return errors.Wrapf(err, "failed to update PipelineRun %s in namespace %s to mark it as cancelled", prName, ns)
+		return current, nil, errors.Wrapf(err, "failed to update PipelineRun %s in namespace %s to mark it as cancelled", prName, ns)
 	}
-	return nil
+	return current, cancelled, nil
 }